@@ -11,25 +11,54 @@ func main() {
 	args := os.Args[1:]
 	if len(args) < 1 {
 		printUsage()
-		os.Exit(1)
+		exit(&jitt.Error{Code: jitt.ExitUsage})
 	}
 
 	switch args[0] {
 	case "init":
-		jitt.HandleInit(args[1:])
+		exit(jitt.HandleInit(jitt.StdIO(), args[1:]))
 	case "config":
-		jitt.HandleConfig(args[1:])
+		exit(jitt.HandleConfig(jitt.StdIO(), args[1:]))
 	case "doctor":
-		jitt.HandleDoctor(args[1:])
+		exit(jitt.HandleDoctor(jitt.StdIO(), args[1:]))
+	case "status":
+		exit(jitt.HandleStatus(jitt.StdIO(), args[1:]))
+	case "validate":
+		exit(jitt.HandleValidate(jitt.StdIO(), args[1:]))
+	case "ci":
+		exit(jitt.HandleCI(jitt.StdIO(), args[1:]))
+	case "transition":
+		exit(jitt.HandleTransition(jitt.StdIO(), args[1:]))
+	case "reword":
+		exit(jitt.HandleReword(jitt.StdIO(), args[1:]))
+	case "log":
+		exit(jitt.HandleLog(jitt.StdIO(), args[1:]))
+	case "changelog":
+		exit(jitt.HandleChangelog(jitt.StdIO(), args[1:]))
+	case "audit":
+		exit(jitt.HandleAudit(jitt.StdIO(), args[1:]))
+	case "worklog":
+		exit(jitt.HandleWorklog(jitt.StdIO(), args[1:]))
+	case "pr":
+		exit(jitt.HandlePR(jitt.StdIO(), args[1:]))
+	case "hook":
+		exit(jitt.HandleHook(jitt.StdIO(), args[1:]))
+	case "server-hook":
+		exit(jitt.HandleServerHook(jitt.StdIO(), args[1:]))
 	case "help", "--help", "-h":
 		printUsage()
 	default:
 		fmt.Fprintf(os.Stderr, "jitt: unknown command %q\n\n", args[0])
 		printUsage()
-		os.Exit(1)
+		exit(&jitt.Error{Code: jitt.ExitUsage})
 	}
 }
 
+// exit ends jitt with the code for a handler's error, reporting it on stderr
+func exit(err error) {
+	os.Exit(jitt.ReportError(os.Stderr, err))
+}
+
 func printUsage() {
 	fmt.Println("jitt - Jira + Git + Tiny Tooling")
 	fmt.Println()
@@ -39,13 +68,39 @@ func printUsage() {
 	fmt.Println("  init [project]    Initialize .jitt.yaml configuration file")
 	fmt.Println("  config [key] [value]  Get or set configuration values")
 	fmt.Println("  doctor            Check project setup and configuration")
+	fmt.Println("  status            Show branch, ticket, hooks and configuration at a glance")
+	fmt.Println("  validate <file>   Check a commit message (used by the commit-msg hook)")
+	fmt.Println("  ci check          Check the commits and branch of a CI build or pull request")
+	fmt.Println("  transition <ticket> <status>  Move a Jira ticket to a new status")
+	fmt.Println("  reword <range>    Add a ticket key to commit messages that lack one")
+	fmt.Println("  hook <name>       Run or install (hook install) jitt's git hooks")
+	fmt.Println("  server-hook <name>  Check pushes from a server's pre-receive or update hook")
+	fmt.Println("  log [range]       List commits with the tickets they reference")
+	fmt.Println("  changelog [range] Group commits by ticket as Markdown (default: since the last tag)")
+	fmt.Println("  audit             Report how many commits reference tickets, by author, month and project")
+	fmt.Println("  worklog           Estimate time spent per ticket from your commits")
+	fmt.Println("  pr describe       Generate a pull request description from commits and ticket")
 	fmt.Println("  help              Show this help message")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  jitt init         # Create .jitt.yaml file with empty project")
 	fmt.Println("  jitt init ABC     # Create .jitt.yaml file with project=ABC")
+	fmt.Println("  jitt init --projects  # Also map monorepo paths to projects interactively")
+	fmt.Println("  jitt init --interactive  # Walk through the settings, suggesting what history uses")
 	fmt.Println("  jitt config       # Show all configuration")
 	fmt.Println("  jitt config project       # Show current project")
 	fmt.Println("  jitt config project XYZ   # Set project to XYZ")
+	fmt.Println("  jitt init --from ../jitt-base.yaml  # Extend a shared base config")
+	fmt.Println("  jitt config update-base --ref v2  # Re-vendor a git base config at a new ref")
+	fmt.Println("  jitt config --explain services/billing  # Show where each setting comes from")
+	fmt.Println("  jitt validate --fix .git/COMMIT_EDITMSG  # Move the ticket key into commit.format")
+	fmt.Println("  jitt validate --range origin/main..HEAD --report sarif  # Also write jitt.sarif")
 	fmt.Println("  jitt doctor       # Check if setup is correct")
+	fmt.Println("  jitt transition ABC-123 \"In Review\"  # Transition a ticket")
+	fmt.Println("  jitt reword origin/main..HEAD --ticket ABC-123  # Add ABC-123 to untagged commits")
+	fmt.Println("  jitt hook install # Install git hooks that run workflow transitions")
+	fmt.Println("  jitt server-hook pre-receive --config /etc/jitt.yaml  # Enforce the rules on pushes")
+	fmt.Println("  jitt audit --since 2026-01-01 --format csv  # Ticket coverage for a dashboard")
+	fmt.Println("  jitt worklog --since monday --submit  # Review and submit this week's time")
+	fmt.Println("  jitt pr describe --output pr.md  # Then: gh pr create --body-file pr.md")
 }
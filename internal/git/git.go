@@ -0,0 +1,338 @@
+// Package git runs git commands for jitt and parses their output into
+// structured results. Every call takes a context, so a cancelled hook or
+// command stops the git processes it started.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Repo runs git in a directory
+type Repo struct {
+	// Dir is where git runs; empty means the current directory
+	Dir string
+	// Env holds extra environment variables, such as GIT_DIR
+	Env []string
+}
+
+// New returns a Repo running git in dir
+func New(dir string) *Repo {
+	return &Repo{Dir: dir}
+}
+
+// Error is a git command that failed, with what it printed on stderr
+type Error struct {
+	Args   []string
+	Stderr string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Stderr == "" {
+		return fmt.Sprintf("git %s: %v", e.Args[0], e.Err)
+	}
+	return fmt.Sprintf("git %s: %s", e.Args[0], e.Stderr)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Run runs git with args and returns its stdout without trailing newlines
+func (r *Repo) Run(ctx context.Context, args ...string) (string, error) {
+	return r.RunInput(ctx, "", args...)
+}
+
+// RunInput runs git like Run, feeding it stdin
+func (r *Repo) RunInput(ctx context.Context, stdin string, args ...string) (string, error) {
+	out, err := r.output(ctx, stdin, args...)
+	return strings.TrimRight(string(out), "\n"), err
+}
+
+// output runs git and returns its stdout untouched
+func (r *Repo) output(ctx context.Context, stdin string, args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), r.Env...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		return nil, &Error{Args: args, Stderr: strings.TrimSpace(stderr.String()), Err: err}
+	}
+	return out, nil
+}
+
+// RevParse runs git rev-parse with args, one result per line
+func (r *Repo) RevParse(ctx context.Context, args ...string) ([]string, error) {
+	out, err := r.Run(ctx, append([]string{"rev-parse"}, args...)...)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// ResolveCommit returns the full SHA of the commit rev names
+func (r *Repo) ResolveCommit(ctx context.Context, rev string) (string, error) {
+	return r.Run(ctx, "rev-parse", "--verify", "--quiet", "--end-of-options", rev+"^{commit}")
+}
+
+// IsAncestor reports whether ancestor is reachable from rev, so moving a
+// branch from ancestor to rev is a fast-forward
+func (r *Repo) IsAncestor(ctx context.Context, ancestor, rev string) (bool, error) {
+	_, err := r.Run(ctx, "merge-base", "--is-ancestor", ancestor, rev)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// TopLevel returns the root of the working tree
+func (r *Repo) TopLevel(ctx context.Context) (string, error) {
+	return r.Run(ctx, "rev-parse", "--show-toplevel")
+}
+
+// Path returns where git keeps name inside the git directory (git rev-parse
+// --git-path), honoring core.hooksPath for hooks and worktree layouts
+func (r *Repo) Path(ctx context.Context, name string) (string, error) {
+	return r.Run(ctx, "rev-parse", "--git-path", name)
+}
+
+// HooksPath returns the directory git reads hooks from
+func (r *Repo) HooksPath(ctx context.Context) (string, error) {
+	return r.Path(ctx, "hooks")
+}
+
+// CurrentBranch returns the short name of the checked out branch, or an
+// error on a detached HEAD
+func (r *Repo) CurrentBranch(ctx context.Context) (string, error) {
+	return r.Run(ctx, "symbolic-ref", "--short", "-q", "HEAD")
+}
+
+// Config returns the value of a git config key, or "" when it is not set
+func (r *Repo) Config(ctx context.Context, key string) (string, error) {
+	out, err := r.Run(ctx, "config", "--get", key)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", nil
+	}
+	return out, err
+}
+
+// Ident returns the name and email git will record for the next commit's
+// author or committer (who is "AUTHOR" or "COMMITTER"), honoring the
+// GIT_AUTHOR_* and GIT_COMMITTER_* variables git sets for hooks
+func (r *Repo) Ident(ctx context.Context, who string) (name, email string, err error) {
+	out, err := r.Run(ctx, "var", "GIT_"+who+"_IDENT")
+	if err != nil {
+		return "", "", err
+	}
+	// "Name <email> timestamp zone"
+	name, rest, _ := strings.Cut(out, " <")
+	email, _, _ = strings.Cut(rest, ">")
+	return name, email, nil
+}
+
+// Merging reports whether a merge is in progress, so the next commit is a merge
+func (r *Repo) Merging(ctx context.Context) bool {
+	_, err := r.Run(ctx, "rev-parse", "-q", "--verify", "MERGE_HEAD")
+	return err == nil
+}
+
+// StagedFiles returns the paths staged for the next commit, relative to the root
+func (r *Repo) StagedFiles(ctx context.Context) ([]string, error) {
+	out, err := r.Run(ctx, "diff", "--cached", "--name-only", "-z")
+	if err != nil {
+		return nil, err
+	}
+	return SplitNUL(out), nil
+}
+
+// Ref is a reference listed by Refs
+type Ref struct {
+	// Name is the full name, such as refs/heads/main
+	Name string
+	// Short is the unambiguous short name, such as main or origin/main
+	Short string
+	// SHA is the object the ref points to
+	SHA string
+	// Upstream is the full name of a branch's upstream, if it has one
+	Upstream string
+}
+
+// Refs lists the refs matching patterns (such as refs/heads), or all of them
+func (r *Repo) Refs(ctx context.Context, patterns ...string) ([]Ref, error) {
+	args := append([]string{"for-each-ref", "--format=%(refname)%00%(refname:short)%00%(objectname)%00%(upstream)"}, patterns...)
+	out, err := r.Run(ctx, args...)
+	if err != nil || out == "" {
+		return nil, err
+	}
+
+	var refs []Ref
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("git for-each-ref: unexpected output %q", line)
+		}
+		refs = append(refs, Ref{Name: fields[0], Short: fields[1], SHA: fields[2], Upstream: fields[3]})
+	}
+	return refs, nil
+}
+
+// Commit is a commit listed by Log
+type Commit struct {
+	SHA            string
+	Parents        []string
+	AuthorName     string
+	AuthorEmail    string
+	AuthorDate     time.Time
+	CommitterName  string
+	CommitterEmail string
+	CommitterDate  time.Time
+	// Message is the full message without surrounding blank lines
+	Message string
+}
+
+// Subject returns the first line of the message
+func (c Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return subject
+}
+
+// IsMerge reports whether the commit has more than one parent
+func (c Commit) IsMerge() bool {
+	return len(c.Parents) > 1
+}
+
+// logFormat has Log's fields NUL-terminated; -z ends each commit with one more
+const logFormat = "--format=%H%x00%P%x00%an%x00%ae%x00%aI%x00%cn%x00%ce%x00%cI%x00%B"
+
+// logFields is the number of NUL-terminated fields per commit in logFormat
+const logFields = 9
+
+// Log returns the commits git log selects with args (revisions, ranges and
+// options such as --no-merges), newest first
+func (r *Repo) Log(ctx context.Context, args ...string) ([]Commit, error) {
+	out, err := r.Run(ctx, append([]string{"log", "-z", logFormat}, args...)...)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	return parseLog(out)
+}
+
+func parseLog(out string) ([]Commit, error) {
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	if len(fields)%logFields != 0 {
+		return nil, fmt.Errorf("git log: unexpected output (%d fields)", len(fields))
+	}
+
+	commits := make([]Commit, 0, len(fields)/logFields)
+	for i := 0; i < len(fields); i += logFields {
+		f := fields[i : i+logFields]
+		authored, err := time.Parse(time.RFC3339, f[4])
+		if err != nil {
+			return nil, fmt.Errorf("git log: %w", err)
+		}
+		committed, err := time.Parse(time.RFC3339, f[7])
+		if err != nil {
+			return nil, fmt.Errorf("git log: %w", err)
+		}
+		commits = append(commits, Commit{
+			SHA:            f[0],
+			Parents:        strings.Fields(f[1]),
+			AuthorName:     f[2],
+			AuthorEmail:    f[3],
+			AuthorDate:     authored,
+			CommitterName:  f[5],
+			CommitterEmail: f[6],
+			CommitterDate:  committed,
+			Message:        strings.TrimSpace(f[8]),
+		})
+	}
+	return commits, nil
+}
+
+// Numstat is what a commit changes, as git diff-tree --numstat counts it
+type Numstat struct {
+	// Files are the paths the commit touches, renamed files by their new path
+	Files []string
+	// Lines is the number of lines added and deleted; binary files count none
+	Lines int
+}
+
+// Numstats returns what each commit changes compared with its first parent,
+// following renames, in a single git diff-tree. Commits that change nothing
+// are left out.
+func (r *Repo) Numstats(ctx context.Context, shas []string) (map[string]Numstat, error) {
+	stats := make(map[string]Numstat)
+	if len(shas) == 0 {
+		return stats, nil
+	}
+	out, err := r.RunInput(ctx, strings.Join(shas, "\n")+"\n", "diff-tree", "--stdin", "--numstat", "-z", "-r", "--root", "-M")
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstats(out), nil
+}
+
+// parseNumstats reads the output of diff-tree --stdin --numstat -z: each
+// commit's SHA, then "added\tdeleted\tpath" records, where renames leave the
+// path empty and follow it with the old and new paths
+func parseNumstats(out string) map[string]Numstat {
+	stats := make(map[string]Numstat)
+	var sha string
+	tokens := strings.Split(out, "\x00")
+	for i := 0; i < len(tokens); i++ {
+		parts := strings.SplitN(strings.TrimLeft(tokens[i], "\n"), "\t", 3)
+		if len(parts) != 3 {
+			if token := strings.TrimSpace(tokens[i]); token != "" {
+				sha = token
+			}
+			continue
+		}
+		added, _ := strconv.Atoi(parts[0])
+		deleted, _ := strconv.Atoi(parts[1])
+
+		path := parts[2]
+		if path == "" && i+2 < len(tokens) {
+			path = tokens[i+2]
+			i += 2
+		}
+		stat := stats[sha]
+		stat.Files = append(stat.Files, path)
+		stat.Lines += added + deleted
+		stats[sha] = stat
+	}
+	return stats
+}
+
+// SplitNUL splits NUL-separated output, dropping empty items
+func SplitNUL(out string) []string {
+	var items []string
+	for _, item := range strings.Split(out, "\x00") {
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// IsZeroSHA reports whether sha is the all-zero placeholder git passes to
+// hooks for a ref that does not exist
+func IsZeroSHA(sha string) bool {
+	return strings.Trim(sha, "0") == ""
+}
@@ -0,0 +1,223 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bbommarito/jitt/internal/git/gittest"
+)
+
+var _ = Describe("Repo", func() {
+	var (
+		fixture *gittest.Repo
+		repo    *Repo
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		fixture = gittest.New(GinkgoT())
+		repo = New(fixture.Dir)
+		ctx = context.Background()
+	})
+
+	Describe("Log", func() {
+		It("should return structured commits, newest first", func() {
+			first := fixture.File("app.js", "1\n").Commit("ABC-1 Add app\n\nWith a body.\n\nRefs: ABC-1",
+				gittest.Author("Jane Doe", "jane@example.com"), gittest.Date("2026-01-05T10:00:00Z"))
+			second := fixture.Commit("ABC-2 Tweak", gittest.Committer("Bot", "bot@example.com"))
+
+			commits, err := repo.Log(ctx, "HEAD")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commits).To(HaveLen(2))
+			Expect(commits[0].SHA).To(Equal(second))
+			Expect(commits[0].Parents).To(Equal([]string{first}))
+			Expect(commits[0].CommitterName).To(Equal("Bot"))
+			Expect(commits[1].Message).To(Equal("ABC-1 Add app\n\nWith a body.\n\nRefs: ABC-1"))
+			Expect(commits[1].Subject()).To(Equal("ABC-1 Add app"))
+			Expect(commits[1].AuthorName).To(Equal("Jane Doe"))
+			Expect(commits[1].AuthorEmail).To(Equal("jane@example.com"))
+			Expect(commits[1].AuthorDate.Equal(time.Date(2026, 1, 5, 10, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(commits[1].Parents).To(BeEmpty())
+		})
+
+		It("should pass ranges and options through", func() {
+			base := fixture.Commit("Base")
+			fixture.Branch("feature")
+			fixture.Commit("ABC-3 Feature work")
+			fixture.Checkout("main")
+			fixture.Merge("feature", "Merge feature")
+
+			commits, err := repo.Log(ctx, base+"..HEAD")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commits).To(HaveLen(2))
+			Expect(commits[0].IsMerge()).To(BeTrue())
+
+			commits, err = repo.Log(ctx, "--no-merges", base+"..HEAD")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(commits).To(ConsistOf(HaveField("Message", "ABC-3 Feature work")))
+		})
+
+		It("should return nothing for an empty range", func() {
+			fixture.Commit("Base")
+			Expect(repo.Log(ctx, "HEAD..HEAD")).To(BeEmpty())
+		})
+	})
+
+	Describe("Refs", func() {
+		It("should list refs matching the patterns", func() {
+			sha := fixture.Commit("Base")
+			fixture.Tag("v1.0").Branch("feature/ABC-1")
+
+			refs, err := repo.Refs(ctx, "refs/heads")
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(refs).To(Equal([]Ref{
+				{Name: "refs/heads/feature/ABC-1", Short: "feature/ABC-1", SHA: sha},
+				{Name: "refs/heads/main", Short: "main", SHA: sha},
+			}))
+			Expect(repo.Refs(ctx, "refs/tags")).To(ConsistOf(HaveField("Short", "v1.0")))
+		})
+	})
+
+	Describe("rev-parse", func() {
+		It("should report the branch, top level and hooks path", func() {
+			fixture.Commit("Base")
+			fixture.Git("config", "core.hooksPath", ".githooks")
+
+			Expect(repo.CurrentBranch(ctx)).To(Equal("main"))
+			dir, err := filepath.EvalSymlinks(fixture.Dir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repo.TopLevel(ctx)).To(Equal(dir))
+			Expect(repo.HooksPath(ctx)).To(Equal(".githooks"))
+			Expect(repo.RevParse(ctx, "--abbrev-ref", "HEAD")).To(Equal([]string{"main"}))
+		})
+
+		It("should fail on a detached HEAD", func() {
+			fixture.Commit("Base")
+			fixture.Checkout("HEAD~0^{commit}")
+
+			_, err := repo.CurrentBranch(ctx)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should resolve commits and reject unknown revisions", func() {
+			sha := fixture.Commit("Base")
+
+			Expect(repo.ResolveCommit(ctx, "main")).To(Equal(sha))
+			_, err := repo.ResolveCommit(ctx, "nope")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("IsAncestor", func() {
+		It("should tell fast-forwards from rewritten history", func() {
+			base := fixture.Commit("Base")
+			next := fixture.Commit("Next")
+			fixture.Checkout(base)
+			other := fixture.Commit("Other")
+
+			Expect(repo.IsAncestor(ctx, base, next)).To(BeTrue())
+			Expect(repo.IsAncestor(ctx, next, other)).To(BeFalse())
+			_, err := repo.IsAncestor(ctx, "no-such-rev", next)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("Config", func() {
+		It("should read values and treat missing keys as empty", func() {
+			Expect(repo.Config(ctx, "user.name")).To(Equal("Test User"))
+			Expect(repo.Config(ctx, "jitt.missing")).To(BeEmpty())
+		})
+	})
+
+	Describe("Ident", func() {
+		It("should return the identities of the next commit, honoring git's variables", func() {
+			name, email, err := repo.Ident(ctx, "AUTHOR")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(name).To(Equal("Test User"))
+			Expect(email).To(Equal("test@example.com"))
+
+			bot := &Repo{Dir: fixture.Dir, Env: []string{"GIT_COMMITTER_EMAIL=bot@example.com"}}
+			_, email, err = bot.Ident(ctx, "COMMITTER")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(email).To(Equal("bot@example.com"))
+		})
+	})
+
+	Describe("Merging", func() {
+		It("should tell whether a merge is in progress", func() {
+			fixture.File("a.txt", "1\n").Commit("Base")
+			fixture.Branch("topic").File("b.txt", "1\n").Commit("Topic")
+			fixture.Checkout("main").File("c.txt", "1\n").Commit("Main")
+			Expect(repo.Merging(ctx)).To(BeFalse())
+
+			fixture.Git("merge", "--no-commit", "topic")
+			Expect(repo.Merging(ctx)).To(BeTrue())
+		})
+	})
+
+	Describe("Numstats", func() {
+		It("should count changed lines per commit and follow renames", func() {
+			first := fixture.File("web/app.js", "1\n2\n3\n").File("old.txt", "a\nb\nc\nd\ne\n").Commit("Add files")
+			fixture.Git("mv", "old.txt", "new.txt")
+			second := fixture.File("web/app.js", "1\n2\n4\n").Commit("Move and edit")
+			empty := fixture.Commit("Nothing")
+
+			stats, err := repo.Numstats(ctx, []string{first, second, empty})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats).To(Equal(map[string]Numstat{
+				first:  {Files: []string{"old.txt", "web/app.js"}, Lines: 8},
+				second: {Files: []string{"new.txt", "web/app.js"}, Lines: 2},
+			}))
+		})
+
+		It("should parse binary files and renames", func() {
+			stats := parseNumstats("abc\x003\t1\tweb/app.js\x000\t0\t\x00old.txt\x00docs/new.txt\x00-\t-\tlogo.png\x00")
+
+			Expect(stats).To(Equal(map[string]Numstat{
+				"abc": {Files: []string{"web/app.js", "docs/new.txt", "logo.png"}, Lines: 4},
+			}))
+		})
+	})
+
+	Describe("StagedFiles", func() {
+		It("should list staged paths, even unusual ones", func() {
+			fixture.File("web/app.js", "1\n").File("docs/with space.md", "x\n")
+			Expect(repo.StagedFiles(ctx)).To(ConsistOf("web/app.js", "docs/with space.md"))
+		})
+	})
+
+	Describe("errors", func() {
+		It("should report git's stderr", func() {
+			_, err := repo.Run(ctx, "log", "no-such-branch")
+
+			var gitErr *Error
+			Expect(errors.As(err, &gitErr)).To(BeTrue())
+			Expect(gitErr.Args[0]).To(Equal("log"))
+			Expect(err.Error()).To(HavePrefix("git log: fatal:"))
+		})
+
+		It("should stop when the context is cancelled", func() {
+			cancelled, cancel := context.WithCancel(ctx)
+			cancel()
+
+			_, err := repo.Run(cancelled, "status")
+			Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		})
+	})
+})
+
+var _ = DescribeTable("IsZeroSHA",
+	func(sha string, expected bool) {
+		Expect(IsZeroSHA(sha)).To(Equal(expected))
+	},
+	Entry("SHA-1 placeholder", "0000000000000000000000000000000000000000", true),
+	Entry("real SHA", "3e376ffd5daac7e62837f65530012af805dcab365", false),
+)
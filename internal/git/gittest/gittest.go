@@ -0,0 +1,189 @@
+// Package gittest builds real, throwaway git repositories for tests: each
+// Repo lives in a temporary directory and is filled through scripted commits.
+//
+//	repo := gittest.New(GinkgoT())
+//	repo.File("web/app.js", "1\n").Commit("ABC-1 Add app", gittest.Author("Jane Doe", "jane@example.com"))
+//	repo.Branch("feature/ABC-2").Commit("ABC-2 Tweak", gittest.Date("2026-01-05T10:00:00Z"))
+package gittest
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// TB is the part of testing.TB (and GinkgoT) a Repo needs
+type TB interface {
+	Helper()
+	TempDir() string
+	Fatalf(format string, args ...any)
+}
+
+// Repo is a git repository in a temporary directory, on branch main with a
+// local identity and commit signing disabled
+type Repo struct {
+	Dir string
+	t   TB
+}
+
+// New creates an empty repository in a new temporary directory
+func New(t TB) *Repo {
+	t.Helper()
+	return Init(t, t.TempDir())
+}
+
+// Init creates an empty repository in dir
+func Init(t TB, dir string) *Repo {
+	t.Helper()
+	r := &Repo{Dir: dir, t: t}
+	r.Git("init", "-q", "-b", "main")
+	r.Git("config", "user.name", "Test User")
+	r.Git("config", "user.email", "test@example.com")
+	r.Git("config", "commit.gpgsign", "false")
+	r.Git("config", "tag.gpgsign", "false")
+	return r
+}
+
+// Git runs a git command in the repository and returns its trimmed output,
+// failing the test if it fails
+func (r *Repo) Git(args ...string) string {
+	r.t.Helper()
+	return r.gitEnv(nil, args...)
+}
+
+func (r *Repo) gitEnv(env []string, args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), env...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// File writes content to a path relative to the repository root, creating
+// directories as needed, and stages it
+func (r *Repo) File(path, content string) *Repo {
+	r.t.Helper()
+	full := filepath.Join(r.Dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		r.t.Fatalf("creating %s: %v", path, err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+		r.t.Fatalf("writing %s: %v", path, err)
+	}
+	r.Git("add", "--", path)
+	return r
+}
+
+// Remove deletes a file and stages the deletion
+func (r *Repo) Remove(path string) *Repo {
+	r.t.Helper()
+	r.Git("rm", "-q", "--", path)
+	return r
+}
+
+// CommitOption changes how Commit records a commit
+type CommitOption func(*commitOptions)
+
+type commitOptions struct {
+	env []string
+}
+
+// Author sets the commit's author
+func Author(name, email string) CommitOption {
+	return func(o *commitOptions) {
+		o.env = append(o.env, "GIT_AUTHOR_NAME="+name, "GIT_AUTHOR_EMAIL="+email)
+	}
+}
+
+// Committer sets the commit's committer
+func Committer(name, email string) CommitOption {
+	return func(o *commitOptions) {
+		o.env = append(o.env, "GIT_COMMITTER_NAME="+name, "GIT_COMMITTER_EMAIL="+email)
+	}
+}
+
+// Date sets the author and committer dates, in any format git accepts
+func Date(date string) CommitOption {
+	return func(o *commitOptions) {
+		o.env = append(o.env, "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+	}
+}
+
+// AuthorDate sets just the author date, as when a commit was later rebased;
+// it overrides Date when given after it
+func AuthorDate(date string) CommitOption {
+	return func(o *commitOptions) {
+		o.env = append(o.env, "GIT_AUTHOR_DATE="+date)
+	}
+}
+
+// Commit records the staged changes (if any) with message and returns the
+// new commit's SHA
+func (r *Repo) Commit(message string, opts ...CommitOption) string {
+	r.t.Helper()
+	var o commitOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	r.gitEnv(o.env, "commit", "-q", "--allow-empty", "-m", message)
+	return r.Git("rev-parse", "HEAD")
+}
+
+// Branch creates a branch at HEAD and checks it out
+func (r *Repo) Branch(name string) *Repo {
+	r.t.Helper()
+	r.Git("checkout", "-q", "-b", name)
+	return r
+}
+
+// Checkout checks out a branch or commit
+func (r *Repo) Checkout(rev string) *Repo {
+	r.t.Helper()
+	r.Git("checkout", "-q", rev)
+	return r
+}
+
+// Merge merges rev into the current branch with a merge commit and returns its SHA
+func (r *Repo) Merge(rev, message string) string {
+	r.t.Helper()
+	r.Git("merge", "-q", "--no-ff", "-m", message, rev)
+	return r.Git("rev-parse", "HEAD")
+}
+
+// Tag creates a lightweight tag at HEAD
+func (r *Repo) Tag(name string) *Repo {
+	r.t.Helper()
+	r.Git("tag", name)
+	return r
+}
+
+// CloneBare makes a bare clone of the repository in a new temporary
+// directory, the way a server holds it. Its Repo only runs Git.
+func (r *Repo) CloneBare() *Repo {
+	r.t.Helper()
+	dir := r.t.TempDir()
+	r.Git("clone", "-q", "--bare", r.Dir, dir)
+	return &Repo{Dir: dir, t: r.t}
+}
+
+// Worktree adds a linked worktree on a new branch in a new temporary directory
+func (r *Repo) Worktree(branch string) *Repo {
+	r.t.Helper()
+	dir := filepath.Join(r.t.TempDir(), "worktree")
+	r.Git("worktree", "add", "-q", "-b", branch, dir)
+	return &Repo{Dir: dir, t: r.t}
+}
+
+// Submodule adds sub as a submodule at path and commits it, returning the
+// submodule's checkout inside this repository
+func (r *Repo) Submodule(sub *Repo, path string) *Repo {
+	r.t.Helper()
+	r.Git("-c", "protocol.file.allow=always", "submodule", "add", "-q", sub.Dir, path)
+	r.Commit("Add submodule " + path)
+	return &Repo{Dir: filepath.Join(r.Dir, path), t: r.t}
+}
@@ -0,0 +1,93 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrNotRepository is returned by Locate outside any git repository
+var ErrNotRepository = errors.New("not a git repository")
+
+// Kind is the layout of a repository found by Locate
+type Kind string
+
+const (
+	// KindWorkTree is an ordinary repository with its main working tree
+	KindWorkTree Kind = "work tree"
+	// KindLinkedWorktree is a working tree added with git worktree add
+	KindLinkedWorktree Kind = "linked worktree"
+	// KindSubmodule is the working tree of a submodule
+	KindSubmodule Kind = "submodule"
+	// KindBare is a repository without a working tree, as on a server
+	KindBare Kind = "bare"
+	// KindGitDir is the inside of a non-bare repository's git directory
+	KindGitDir Kind = "git directory"
+)
+
+// Location describes the repository git finds from a Repo's directory,
+// honoring GIT_DIR, GIT_WORK_TREE and .git files the way git itself does
+type Location struct {
+	Kind Kind
+	// GitDir is the absolute path of the git directory; for a linked
+	// worktree it is the worktree's own directory under CommonDir
+	GitDir string
+	// CommonDir is the absolute path of the directory shared by all of the
+	// repository's worktrees, holding its objects, refs and config
+	CommonDir string
+	// WorkTree is the root of the working tree, or "" without one
+	WorkTree string
+	// Superproject is the root of the superproject's working tree for a submodule
+	Superproject string
+}
+
+// HasWorkTree reports whether the repository has a working tree to run in
+func (l Location) HasWorkTree() bool {
+	return l.WorkTree != ""
+}
+
+// Locate finds the repository containing the Repo's directory, or returns
+// ErrNotRepository when there is none
+func (r *Repo) Locate(ctx context.Context) (Location, error) {
+	lines, err := r.RevParse(ctx, "--path-format=absolute", "--absolute-git-dir", "--git-common-dir",
+		"--is-bare-repository", "--is-inside-work-tree")
+	var gitErr *Error
+	if errors.As(err, &gitErr) && strings.Contains(gitErr.Stderr, "not a git repository") {
+		return Location{}, ErrNotRepository
+	}
+	if err != nil {
+		return Location{}, err
+	}
+	if len(lines) != 4 {
+		return Location{}, errors.New("unexpected git rev-parse output: " + strings.Join(lines, " "))
+	}
+
+	loc := Location{GitDir: lines[0], CommonDir: lines[1]}
+	switch {
+	case lines[2] == "true":
+		loc.Kind = KindBare
+		return loc, nil
+	case lines[3] != "true":
+		loc.Kind = KindGitDir
+		return loc, nil
+	}
+
+	paths, err := r.RevParse(ctx, "--show-toplevel", "--show-superproject-working-tree")
+	if err != nil {
+		return Location{}, err
+	}
+	loc.WorkTree = paths[0]
+	if len(paths) > 1 {
+		loc.Superproject = paths[1]
+	}
+
+	switch {
+	case loc.Superproject != "":
+		loc.Kind = KindSubmodule
+	case loc.GitDir != loc.CommonDir:
+		loc.Kind = KindLinkedWorktree
+	default:
+		loc.Kind = KindWorkTree
+	}
+	return loc, nil
+}
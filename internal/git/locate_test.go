@@ -0,0 +1,123 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bbommarito/jitt/internal/git/gittest"
+)
+
+var _ = Describe("Locate", func() {
+	var (
+		fixture *gittest.Repo
+		ctx     context.Context
+	)
+
+	realPath := func(path string) string {
+		resolved, err := filepath.EvalSymlinks(path)
+		Expect(err).NotTo(HaveOccurred())
+		return resolved
+	}
+
+	BeforeEach(func() {
+		fixture = gittest.New(GinkgoT())
+		fixture.Commit("Initial commit")
+		ctx = context.Background()
+	})
+
+	It("should find an ordinary work tree from a subdirectory", func() {
+		fixture.File("web/app.js", "1\n")
+
+		loc, err := New(filepath.Join(fixture.Dir, "web")).Locate(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loc.Kind).To(Equal(KindWorkTree))
+		Expect(loc.WorkTree).To(Equal(realPath(fixture.Dir)))
+		Expect(loc.GitDir).To(Equal(realPath(filepath.Join(fixture.Dir, ".git"))))
+		Expect(loc.CommonDir).To(Equal(loc.GitDir))
+		Expect(loc.HasWorkTree()).To(BeTrue())
+	})
+
+	It("should follow a linked worktree's .git file", func() {
+		worktree := fixture.Worktree("feature")
+
+		loc, err := New(worktree.Dir).Locate(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loc.Kind).To(Equal(KindLinkedWorktree))
+		Expect(loc.WorkTree).To(Equal(realPath(worktree.Dir)))
+		Expect(loc.CommonDir).To(Equal(realPath(filepath.Join(fixture.Dir, ".git"))))
+		Expect(loc.GitDir).To(HavePrefix(loc.CommonDir + string(filepath.Separator) + "worktrees"))
+	})
+
+	It("should recognise a submodule", func() {
+		lib := gittest.New(GinkgoT())
+		lib.Commit("Library")
+		sub := fixture.Submodule(lib, "vendor/lib")
+
+		loc, err := New(sub.Dir).Locate(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loc.Kind).To(Equal(KindSubmodule))
+		Expect(loc.WorkTree).To(Equal(realPath(sub.Dir)))
+		Expect(loc.Superproject).To(Equal(realPath(fixture.Dir)))
+	})
+
+	It("should recognise a bare repository", func() {
+		bare := fixture.CloneBare()
+
+		loc, err := New(bare.Dir).Locate(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loc.Kind).To(Equal(KindBare))
+		Expect(loc.GitDir).To(Equal(realPath(bare.Dir)))
+		Expect(loc.HasWorkTree()).To(BeFalse())
+	})
+
+	It("should recognise the inside of a git directory", func() {
+		loc, err := New(filepath.Join(fixture.Dir, ".git")).Locate(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loc.Kind).To(Equal(KindGitDir))
+		Expect(loc.HasWorkTree()).To(BeFalse())
+	})
+
+	It("should honor GIT_DIR and GIT_WORK_TREE", func() {
+		elsewhere := GinkgoT().TempDir()
+		repo := &Repo{Dir: elsewhere, Env: []string{
+			"GIT_DIR=" + filepath.Join(fixture.Dir, ".git"),
+			"GIT_WORK_TREE=" + elsewhere,
+		}}
+
+		loc, err := repo.Locate(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loc.Kind).To(Equal(KindWorkTree))
+		Expect(loc.WorkTree).To(Equal(realPath(elsewhere)))
+		Expect(loc.GitDir).To(Equal(realPath(filepath.Join(fixture.Dir, ".git"))))
+	})
+
+	It("should treat GIT_DIR alone as a bare setup when the directory is bare", func() {
+		bare := fixture.CloneBare()
+		repo := &Repo{Dir: GinkgoT().TempDir(), Env: []string{"GIT_DIR=" + bare.Dir}}
+
+		loc, err := repo.Locate(ctx)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loc.Kind).To(Equal(KindBare))
+	})
+
+	It("should not take a stray .git directory for a repository", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.Mkdir(filepath.Join(dir, ".git"), 0o755)).To(Succeed())
+		repo := &Repo{Dir: dir, Env: []string{"GIT_CEILING_DIRECTORIES=" + filepath.Dir(dir)}}
+
+		_, err := repo.Locate(ctx)
+
+		Expect(err).To(MatchError(ErrNotRepository))
+	})
+})
@@ -0,0 +1,221 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tree is the tree of a commit as an fs.FS, so code that reads files can read
+// them as committed rather than from the working tree. The tree is listed once,
+// on first use; file contents are read as they are opened. Submodules are left
+// out.
+type Tree struct {
+	repo *Repo
+	// ctx bounds the git commands run on behalf of fs.FS methods, which take none
+	ctx context.Context
+	rev string
+
+	once    sync.Once
+	entries map[string]treeEntry
+	err     error
+}
+
+// treeEntry is a blob or tree listed by git ls-tree
+type treeEntry struct {
+	object string
+	size   int64
+	dir    bool
+}
+
+// Tree returns the tree of the commit rev names as an fs.FS
+func (r *Repo) Tree(ctx context.Context, rev string) *Tree {
+	return &Tree{repo: r, ctx: ctx, rev: rev}
+}
+
+// load lists the whole tree: git ls-tree -z prints
+// "<mode> SP <type> SP <object> SP <size> TAB <path>" for each entry
+func (t *Tree) load() error {
+	t.once.Do(func() {
+		out, err := t.repo.Run(t.ctx, "ls-tree", "-r", "-t", "-l", "-z", "--full-tree", "--end-of-options", t.rev+"^{commit}")
+		if err != nil {
+			t.err = err
+			return
+		}
+
+		t.entries = map[string]treeEntry{".": {dir: true}}
+		for _, record := range SplitNUL(out) {
+			meta, name, ok := strings.Cut(record, "\t")
+			fields := strings.Fields(meta)
+			if !ok || len(fields) != 4 {
+				continue
+			}
+			switch fields[1] {
+			case "tree":
+				t.entries[name] = treeEntry{object: fields[2], dir: true}
+			case "blob":
+				size, _ := strconv.ParseInt(fields[3], 10, 64)
+				t.entries[name] = treeEntry{object: fields[2], size: size}
+			}
+		}
+	})
+	return t.err
+}
+
+// lookup finds the entry for a path, reporting errors as op on name
+func (t *Tree) lookup(op, name string) (treeEntry, error) {
+	if !fs.ValidPath(name) {
+		return treeEntry{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if err := t.load(); err != nil {
+		return treeEntry{}, &fs.PathError{Op: op, Path: name, Err: err}
+	}
+	entry, ok := t.entries[name]
+	if !ok {
+		return treeEntry{}, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	return entry, nil
+}
+
+// Open implements fs.FS
+func (t *Tree) Open(name string) (fs.File, error) {
+	entry, err := t.lookup("open", name)
+	if err != nil {
+		return nil, err
+	}
+	info := treeInfo{name: path.Base(name), entry: entry}
+	if entry.dir {
+		return &treeDir{info: info, entries: t.children(name)}, nil
+	}
+
+	data, err := t.read(name, entry)
+	if err != nil {
+		return nil, err
+	}
+	return &treeFile{info: info, Reader: bytes.NewReader(data)}, nil
+}
+
+// ReadFile implements fs.ReadFileFS
+func (t *Tree) ReadFile(name string) ([]byte, error) {
+	entry, err := t.lookup("read", name)
+	if err != nil {
+		return nil, err
+	}
+	if entry.dir {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: errIsDir}
+	}
+	return t.read(name, entry)
+}
+
+// Stat implements fs.StatFS without reading the file
+func (t *Tree) Stat(name string) (fs.FileInfo, error) {
+	entry, err := t.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return treeInfo{name: path.Base(name), entry: entry}, nil
+}
+
+// ReadDir implements fs.ReadDirFS
+func (t *Tree) ReadDir(name string) ([]fs.DirEntry, error) {
+	entry, err := t.lookup("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.dir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errNotDir}
+	}
+	return t.children(name), nil
+}
+
+func (t *Tree) read(name string, entry treeEntry) ([]byte, error) {
+	data, err := t.repo.output(t.ctx, "", "cat-file", "blob", entry.object)
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+	return data, nil
+}
+
+// children lists the entries directly inside dir, sorted by name
+func (t *Tree) children(dir string) []fs.DirEntry {
+	var list []fs.DirEntry
+	for name, entry := range t.entries {
+		if name != "." && path.Dir(name) == dir {
+			list = append(list, fs.FileInfoToDirEntry(treeInfo{name: path.Base(name), entry: entry}))
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list
+}
+
+var (
+	errIsDir  = errors.New("is a directory")
+	errNotDir = errors.New("not a directory")
+)
+
+// treeInfo describes a tree entry; committed files have no modification time
+type treeInfo struct {
+	name  string
+	entry treeEntry
+}
+
+func (i treeInfo) Name() string       { return i.name }
+func (i treeInfo) Size() int64        { return i.entry.size }
+func (i treeInfo) ModTime() time.Time { return time.Time{} }
+func (i treeInfo) IsDir() bool        { return i.entry.dir }
+func (i treeInfo) Sys() any           { return nil }
+
+func (i treeInfo) Mode() fs.FileMode {
+	if i.entry.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+// treeFile is an open blob
+type treeFile struct {
+	info treeInfo
+	*bytes.Reader
+}
+
+func (f *treeFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *treeFile) Close() error               { return nil }
+
+// treeDir is an open tree
+type treeDir struct {
+	info    treeInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *treeDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *treeDir) Close() error               { return nil }
+
+func (d *treeDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errIsDir}
+}
+
+// ReadDir implements fs.ReadDirFile
+func (d *treeDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return rest[:n], nil
+}
@@ -0,0 +1,58 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bbommarito/jitt/internal/git/gittest"
+)
+
+var _ = Describe("Tree", func() {
+	var (
+		fixture *gittest.Repo
+		repo    *Repo
+		ctx     context.Context
+		rev     string
+	)
+
+	BeforeEach(func() {
+		fixture = gittest.New(GinkgoT())
+		repo = New(fixture.Dir)
+		ctx = context.Background()
+		rev = fixture.File(".jitt.yaml", "jira:\n  project: ABC\n").
+			File("services/billing/.jitt.yaml", "jira:\n  project: BILL\n").
+			File("services/billing/main.go", "package main\n").
+			Commit("ABC-1 Add configs")
+	})
+
+	It("should behave as an fs.FS of the committed files", func() {
+		Expect(fstest.TestFS(repo.Tree(ctx, rev), ".jitt.yaml", "services/billing/.jitt.yaml", "services/billing/main.go")).To(Succeed())
+	})
+
+	It("should read files as committed, not as in the working tree", func() {
+		fixture.File(".jitt.yaml", "jira:\n  project: XYZ\n").File("untracked.txt", "new\n")
+		tree := repo.Tree(ctx, rev)
+
+		Expect(fs.ReadFile(tree, ".jitt.yaml")).To(Equal([]byte("jira:\n  project: ABC\n")))
+		_, err := fs.Stat(tree, "untracked.txt")
+		Expect(err).To(MatchError(fs.ErrNotExist))
+
+		info, err := fs.Stat(tree, "services")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.IsDir()).To(BeTrue())
+	})
+
+	It("should report a revision that does not exist", func() {
+		_, err := fs.ReadFile(repo.Tree(ctx, "no-such-rev"), ".jitt.yaml")
+
+		var gitErr *Error
+		Expect(err).To(BeAssignableToTypeOf(&fs.PathError{}))
+		Expect(err).To(MatchError(ContainSubstring("git ls-tree")))
+		Expect(errors.As(err, &gitErr)).To(BeTrue())
+	})
+})
@@ -0,0 +1,231 @@
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultTimeout = 15 * time.Second
+
+// Client is a minimal Jira REST (v2) client covering the handful of calls jitt needs
+type Client struct {
+	baseURL    string
+	user       string
+	token      string
+	httpClient *http.Client
+}
+
+// Issue is the subset of a Jira issue that jitt cares about
+type Issue struct {
+	Key    string      `json:"key"`
+	Fields IssueFields `json:"fields"`
+}
+
+// IssueFields holds the issue fields requested by Issue
+type IssueFields struct {
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	Status      Status `json:"status"`
+	Assignee    *User  `json:"assignee"`
+	// Extra holds the text value of any additional fields asked for, such as
+	// a custom "acceptance criteria" field
+	Extra map[string]string `json:"-"`
+}
+
+// Status is a Jira workflow status
+type Status struct {
+	Name string `json:"name"`
+}
+
+// User is a Jira user reference
+type User struct {
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// Transition is a workflow transition available on an issue
+type Transition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	To   Status `json:"to"`
+}
+
+// APIError is returned when Jira answers with a non-2xx status
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	body := strings.TrimSpace(e.Body)
+	if body == "" {
+		return fmt.Sprintf("jira: unexpected status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("jira: unexpected status %d: %s", e.StatusCode, body)
+}
+
+// NewClient creates a client for the Jira instance at baseURL. When user is
+// empty the token is sent as a bearer token (Jira Data Center personal access
+// tokens), otherwise basic auth is used (Jira Cloud email + API token).
+func NewClient(baseURL, user, token string) *Client {
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		user:       user,
+		token:      token,
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+// BrowseURL returns the human-facing URL of an issue
+func (c *Client) BrowseURL(key string) string {
+	return c.baseURL + "/browse/" + key
+}
+
+// Issue fetches an issue by key, along with any extra (e.g. custom) fields
+// whose text values should be made available in Fields.Extra
+func (c *Client) Issue(ctx context.Context, key string, extraFields ...string) (*Issue, error) {
+	var raw struct {
+		Key    string                     `json:"key"`
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	fields := append([]string{"summary", "description", "status", "assignee"}, extraFields...)
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "?fields=" + url.QueryEscape(strings.Join(fields, ","))
+	if err := c.do(ctx, http.MethodGet, path, nil, &raw); err != nil {
+		return nil, err
+	}
+
+	issue := &Issue{Key: raw.Key}
+	if data, err := json.Marshal(raw.Fields); err == nil {
+		_ = json.Unmarshal(data, &issue.Fields)
+	}
+	for _, name := range extraFields {
+		var text string
+		if json.Unmarshal(raw.Fields[name], &text) == nil && text != "" {
+			if issue.Fields.Extra == nil {
+				issue.Fields.Extra = make(map[string]string)
+			}
+			issue.Fields.Extra[name] = text
+		}
+	}
+	return issue, nil
+}
+
+// ProjectExists reports whether a project with the given key exists (and is
+// visible to the user)
+func (c *Client) ProjectExists(ctx context.Context, key string) (bool, error) {
+	err := c.do(ctx, http.MethodGet, "/rest/api/2/project/"+url.PathEscape(key), nil, nil)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Transitions lists the transitions currently available on an issue
+func (c *Client) Transitions(ctx context.Context, key string) ([]Transition, error) {
+	var resp struct {
+		Transitions []Transition `json:"transitions"`
+	}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	if err := c.do(ctx, http.MethodGet, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Transitions, nil
+}
+
+// DoTransition applies the transition with the given ID to an issue
+func (c *Client) DoTransition(ctx context.Context, key, transitionID string) error {
+	body := map[string]any{"transition": map[string]string{"id": transitionID}}
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/transitions"
+	return c.do(ctx, http.MethodPost, path, body, nil)
+}
+
+// AddComment posts a plain-text comment on an issue
+func (c *Client) AddComment(ctx context.Context, key, body string) error {
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/comment"
+	return c.do(ctx, http.MethodPost, path, map[string]string{"body": body}, nil)
+}
+
+// Worklog is a time entry logged against an issue. Either TimeSpent (Jira
+// duration notation such as "1h 30m") or TimeSpentSeconds must be set.
+type Worklog struct {
+	TimeSpent        string
+	TimeSpentSeconds int
+	Started          time.Time
+	Comment          string
+}
+
+// jiraTimeLayout is the timestamp format Jira expects for worklog start times
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// AddWorklog logs work against an issue
+func (c *Client) AddWorklog(ctx context.Context, key string, worklog Worklog) error {
+	body := map[string]any{}
+	if worklog.TimeSpent != "" {
+		body["timeSpent"] = worklog.TimeSpent
+	} else {
+		body["timeSpentSeconds"] = worklog.TimeSpentSeconds
+	}
+	if !worklog.Started.IsZero() {
+		body["started"] = worklog.Started.Format(jiraTimeLayout)
+	}
+	if worklog.Comment != "" {
+		body["comment"] = worklog.Comment
+	}
+
+	path := "/rest/api/2/issue/" + url.PathEscape(key) + "/worklog"
+	return c.do(ctx, http.MethodPost, path, body, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("jira: encoding request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("jira: building request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.user != "":
+		req.SetBasicAuth(c.user, c.token)
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &APIError{StatusCode: resp.StatusCode, Body: string(data)}
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("jira: decoding response: %w", err)
+	}
+	return nil
+}
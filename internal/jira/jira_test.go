@@ -0,0 +1,190 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestJira(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Jira Suite")
+}
+
+var _ = Describe("Jira client", func() {
+	var (
+		server   *httptest.Server
+		mux      *http.ServeMux
+		client   *Client
+		lastAuth string
+	)
+
+	BeforeEach(func() {
+		mux = http.NewServeMux()
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lastAuth = r.Header.Get("Authorization")
+			mux.ServeHTTP(w, r)
+		}))
+		client = NewClient(server.URL+"/", "me@example.com", "secret")
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("Issue", func() {
+		It("should decode summary, status and assignee", func() {
+			mux.HandleFunc("GET /rest/api/2/issue/ABC-1", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"key":"ABC-1","fields":{"summary":"Fix it",` +
+					`"status":{"name":"To Do"},"assignee":{"displayName":"Sam"}}}`))
+			})
+
+			issue, err := client.Issue(context.Background(), "ABC-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(issue.Key).To(Equal("ABC-1"))
+			Expect(issue.Fields.Summary).To(Equal("Fix it"))
+			Expect(issue.Fields.Status.Name).To(Equal("To Do"))
+			Expect(issue.Fields.Assignee.DisplayName).To(Equal("Sam"))
+		})
+
+		It("should expose the text of extra fields", func() {
+			var requested string
+			mux.HandleFunc("GET /rest/api/2/issue/ABC-1", func(w http.ResponseWriter, r *http.Request) {
+				requested = r.URL.Query().Get("fields")
+				_, _ = w.Write([]byte(`{"key":"ABC-1","fields":{"description":"Long text",` +
+					`"customfield_10035":"* it works","customfield_1":null}}`))
+			})
+
+			issue, err := client.Issue(context.Background(), "ABC-1", "customfield_10035", "customfield_1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requested).To(Equal("summary,description,status,assignee,customfield_10035,customfield_1"))
+			Expect(issue.Fields.Description).To(Equal("Long text"))
+			Expect(issue.Fields.Extra).To(Equal(map[string]string{"customfield_10035": "* it works"}))
+		})
+
+		It("should use basic auth when a user is configured", func() {
+			mux.HandleFunc("GET /rest/api/2/issue/ABC-1", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"key":"ABC-1"}`))
+			})
+
+			_, err := client.Issue(context.Background(), "ABC-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastAuth).To(HavePrefix("Basic "))
+		})
+
+		It("should use a bearer token when no user is configured", func() {
+			mux.HandleFunc("GET /rest/api/2/issue/ABC-1", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"key":"ABC-1"}`))
+			})
+
+			_, err := NewClient(server.URL, "", "pat").Issue(context.Background(), "ABC-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(lastAuth).To(Equal("Bearer pat"))
+		})
+
+		It("should return an APIError for non-2xx responses", func() {
+			mux.HandleFunc("GET /rest/api/2/issue/ABC-404", func(w http.ResponseWriter, _ *http.Request) {
+				http.Error(w, `{"errorMessages":["Issue does not exist"]}`, http.StatusNotFound)
+			})
+
+			_, err := client.Issue(context.Background(), "ABC-404")
+			var apiErr *APIError
+			Expect(err).To(BeAssignableToTypeOf(apiErr))
+			Expect(err.Error()).To(ContainSubstring("404"))
+			Expect(err.Error()).To(ContainSubstring("Issue does not exist"))
+		})
+	})
+
+	Describe("Transitions", func() {
+		It("should list transitions and apply one by ID", func() {
+			var posted map[string]map[string]string
+			mux.HandleFunc("GET /rest/api/2/issue/ABC-1/transitions", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"transitions":[{"id":"21","name":"Start Progress","to":{"name":"In Progress"}}]}`))
+			})
+			mux.HandleFunc("POST /rest/api/2/issue/ABC-1/transitions", func(w http.ResponseWriter, r *http.Request) {
+				Expect(json.NewDecoder(r.Body).Decode(&posted)).To(Succeed())
+				w.WriteHeader(http.StatusNoContent)
+			})
+
+			transitions, err := client.Transitions(context.Background(), "ABC-1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(transitions).To(HaveLen(1))
+			Expect(transitions[0].To.Name).To(Equal("In Progress"))
+
+			Expect(client.DoTransition(context.Background(), "ABC-1", "21")).To(Succeed())
+			Expect(posted["transition"]["id"]).To(Equal("21"))
+		})
+	})
+
+	Describe("ProjectExists", func() {
+		It("should tell existing projects from missing ones", func() {
+			mux.HandleFunc("GET /rest/api/2/project/ABC", func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(`{"key":"ABC"}`))
+			})
+			mux.HandleFunc("GET /rest/api/2/project/NOPE", func(w http.ResponseWriter, _ *http.Request) {
+				http.Error(w, `{"errorMessages":["No project could be found with key 'NOPE'."]}`, http.StatusNotFound)
+			})
+
+			Expect(client.ProjectExists(context.Background(), "ABC")).To(BeTrue())
+			Expect(client.ProjectExists(context.Background(), "NOPE")).To(BeFalse())
+		})
+
+		It("should return other errors", func() {
+			mux.HandleFunc("GET /rest/api/2/project/ABC", func(w http.ResponseWriter, _ *http.Request) {
+				http.Error(w, "", http.StatusUnauthorized)
+			})
+
+			_, err := client.ProjectExists(context.Background(), "ABC")
+			Expect(err).To(MatchError(ContainSubstring("401")))
+		})
+	})
+
+	Describe("AddComment", func() {
+		It("should post the comment body", func() {
+			var posted map[string]string
+			mux.HandleFunc("POST /rest/api/2/issue/ABC-1/comment", func(w http.ResponseWriter, r *http.Request) {
+				Expect(json.NewDecoder(r.Body).Decode(&posted)).To(Succeed())
+				w.WriteHeader(http.StatusCreated)
+			})
+
+			Expect(client.AddComment(context.Background(), "ABC-1", "fixed null check")).To(Succeed())
+			Expect(posted).To(HaveKeyWithValue("body", "fixed null check"))
+		})
+	})
+
+	Describe("AddWorklog", func() {
+		var posted map[string]any
+
+		BeforeEach(func() {
+			posted = nil
+			mux.HandleFunc("POST /rest/api/2/issue/ABC-1/worklog", func(w http.ResponseWriter, r *http.Request) {
+				Expect(json.NewDecoder(r.Body).Decode(&posted)).To(Succeed())
+				w.WriteHeader(http.StatusCreated)
+			})
+		})
+
+		It("should send Jira duration notation as timeSpent", func() {
+			Expect(client.AddWorklog(context.Background(), "ABC-1", Worklog{TimeSpent: "1h 30m", Comment: "pairing"})).To(Succeed())
+			Expect(posted).To(HaveKeyWithValue("timeSpent", "1h 30m"))
+			Expect(posted).To(HaveKeyWithValue("comment", "pairing"))
+			Expect(posted).NotTo(HaveKey("started"))
+		})
+
+		It("should send seconds and the start time in Jira's format", func() {
+			started := time.Date(2026, 10, 12, 9, 30, 0, 0, time.UTC)
+			Expect(client.AddWorklog(context.Background(), "ABC-1", Worklog{TimeSpentSeconds: 5400, Started: started})).To(Succeed())
+			Expect(posted).To(HaveKeyWithValue("timeSpentSeconds", BeNumerically("==", 5400)))
+			Expect(posted).To(HaveKeyWithValue("started", "2026-10-12T09:30:00.000+0000"))
+		})
+	})
+
+	It("should build browse URLs without a double slash", func() {
+		Expect(client.BrowseURL("ABC-1")).To(Equal(server.URL + "/browse/ABC-1"))
+	})
+})
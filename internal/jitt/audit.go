@@ -0,0 +1,412 @@
+package jitt
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	gitrepo "github.com/bbommarito/jitt/internal/git"
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// noProject labels commits no project mapping or jira.project covers
+const noProject = "(none)"
+
+// auditCommit is a commit as seen by 'jitt audit'
+type auditCommit struct {
+	SHA      string    `json:"sha"`
+	Author   string    `json:"author"`
+	Date     time.Time `json:"date"`
+	Subject  string    `json:"subject"`
+	Keys     []string  `json:"keys"`
+	Projects []string  `json:"projects"`
+	Changes  int       `json:"changes"`
+	// Bypass is the reason the commit gave for skipping jitt's checks
+	Bypass string `json:"bypass,omitempty"`
+
+	// candidates are all keys mentioned where tickets are expected, including
+	// those of projects the config doesn't know
+	candidates []string
+}
+
+// coverage counts the commits of one author, month or project and how many
+// of them reference a ticket
+type coverage struct {
+	Name     string  `json:"name"`
+	Commits  int     `json:"commits"`
+	Tracked  int     `json:"tracked"`
+	Coverage float64 `json:"coverage"`
+}
+
+// unknownKey is a ticket key whose project doesn't exist
+type unknownKey struct {
+	Key     string   `json:"key"`
+	Commits []string `json:"commits"`
+}
+
+// auditReport is the result of 'jitt audit'
+type auditReport struct {
+	Since       string        `json:"since,omitempty"`
+	Until       string        `json:"until,omitempty"`
+	Total       coverage      `json:"total"`
+	Authors     []coverage    `json:"authors"`
+	Months      []coverage    `json:"months"`
+	Projects    []coverage    `json:"projects"`
+	Untracked   []auditCommit `json:"top_untracked"`
+	UnknownKeys []unknownKey  `json:"unknown_keys"`
+	Bypasses    []auditCommit `json:"bypasses"`
+}
+
+// HandleAudit handles the 'jitt audit' command
+func HandleAudit(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	since := fs.String("since", "", "first day to include: YYYY-MM-DD, Nd, a weekday, ... (default: all history)")
+	until := fs.String("until", "", "last day to include (default: today)")
+	format := fs.String("format", "table", "output format: table, csv or json")
+	top := fs.Int("top", 10, "number of untracked commits to list, largest first")
+	checkJira := fs.Bool("jira", false, "ask Jira which projects exist instead of relying on .jitt.yaml")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagError(err)
+	}
+
+	if len(positional) > 1 || *format != "table" && *format != "csv" && *format != "json" {
+		return usageError("Usage: jitt audit [<revision>] [--since YYYY-MM-DD] [--until YYYY-MM-DD] [--format table|csv|json] [--top 10] [--jira]")
+	}
+
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+
+	revs := []string{"HEAD"}
+	if len(positional) == 1 {
+		revs = positional
+	}
+	report := auditReport{}
+	now := time.Now()
+	var from, to time.Time
+	if *since != "" {
+		if from, err = parseDay(*since, now); err != nil {
+			return usageError("Error: invalid --since: %v", err)
+		}
+		report.Since = from.Format(time.DateOnly)
+	}
+	if *until != "" {
+		last, err := parseDay(*until, now)
+		if err != nil {
+			return usageError("Error: invalid --until: %v", err)
+		}
+		report.Until = last.Format(time.DateOnly)
+		to = last.Add(day)
+	}
+
+	commits, err := auditCommits(cfg, from, to, revs...)
+	if err != nil {
+		return failure("Error reading commits: %v", err)
+	}
+
+	exists := projectExistsInConfig(cfg)
+	if *checkJira {
+		if exists, err = projectExistsInJira(cfg); err != nil {
+			return configError("Error: %v", err)
+		}
+	}
+
+	report.summarize(commits, *top)
+	if report.UnknownKeys, err = findUnknownKeys(commits, exists); err != nil {
+		return jiraError(err, "Error checking projects in Jira: %v", err)
+	}
+
+	switch *format {
+	case "csv":
+		err = writeAuditCSV(stdio.Out, report)
+	case "json":
+		encoder := json.NewEncoder(stdio.Out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	default:
+		writeAuditTable(stdio.Out, report)
+	}
+	if err != nil {
+		return failure("Error writing report: %v", err)
+	}
+	return nil
+}
+
+// auditCommits lists the non-merge commits selected by revs and authored
+// within [from, to), newest first, with the tickets they reference, the
+// projects whose paths they touch and any reason they gave for skipping
+// jitt's checks. A zero from or to leaves that end open.
+func auditCommits(cfg *config.Config, from, to time.Time, revs ...string) ([]auditCommit, error) {
+	ctx := context.Background()
+	// git log --since and --until go by committer date; the window and the
+	// months in the report both go by author date
+	log, err := repo.Log(ctx, append([]string{"--no-merges"}, revs...)...)
+	if err != nil {
+		return nil, err
+	}
+	var selected []gitrepo.Commit
+	var shas []string
+	for _, c := range log {
+		if !from.IsZero() && c.AuthorDate.Before(from) || !to.IsZero() && !c.AuthorDate.Before(to) {
+			continue
+		}
+		selected = append(selected, c)
+		shas = append(shas, c.SHA)
+	}
+	stats, err := repo.Numstats(ctx, shas)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]auditCommit, 0, len(selected))
+	for _, c := range selected {
+		stat := stats[c.SHA]
+		commit := auditCommit{
+			SHA:        c.SHA,
+			Author:     c.AuthorName,
+			Date:       c.AuthorDate,
+			Subject:    c.Subject(),
+			Keys:       lib.ReferencedKeys(cfg, c.Message),
+			Projects:   lib.OwningProjects(cfg, stat.Files),
+			Changes:    stat.Lines,
+			candidates: lib.CandidateKeys(cfg, c.Message),
+		}
+		commit.Bypass, _ = lib.BypassReason(c.Message)
+		if len(commit.Projects) == 0 && cfg.Jira.Project != "" {
+			commit.Projects = []string{cfg.Jira.Project}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// projectExistsInConfig treats the projects in .jitt.yaml as the ones that
+// exist; with none configured, every project does
+func projectExistsInConfig(cfg *config.Config) func(string) (bool, error) {
+	known := cfg.KnownProjects()
+	return func(project string) (bool, error) {
+		return len(known) == 0 || containsString(known, project), nil
+	}
+}
+
+// projectExistsInJira asks Jira about each project once
+func projectExistsInJira(cfg *config.Config) (func(string) (bool, error), error) {
+	client, err := newJiraClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cache := make(map[string]bool)
+	return func(project string) (bool, error) {
+		if exists, ok := cache[project]; ok {
+			return exists, nil
+		}
+		exists, err := client.ProjectExists(context.Background(), project)
+		if err != nil {
+			return false, err
+		}
+		cache[project] = exists
+		return exists, nil
+	}, nil
+}
+
+// findUnknownKeys lists the keys whose project doesn't exist, with the short
+// SHAs of the commits mentioning them
+func findUnknownKeys(commits []auditCommit, exists func(string) (bool, error)) ([]unknownKey, error) {
+	byKey := make(map[string]*unknownKey)
+	var keys []string
+	for _, c := range commits {
+		for _, key := range c.candidates {
+			ok, err := exists(lib.KeyProject(key))
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				continue
+			}
+			if byKey[key] == nil {
+				byKey[key] = &unknownKey{Key: key}
+				keys = append(keys, key)
+			}
+			byKey[key].Commits = append(byKey[key].Commits, c.SHA[:7])
+		}
+	}
+
+	sort.Strings(keys)
+	unknown := make([]unknownKey, 0, len(keys))
+	for _, key := range keys {
+		unknown = append(unknown, *byKey[key])
+	}
+	return unknown, nil
+}
+
+// summarize computes the coverage figures and picks the top untracked commits
+func (r *auditReport) summarize(commits []auditCommit, top int) {
+	authors := make(map[string]*coverage)
+	months := make(map[string]*coverage)
+	projects := make(map[string]*coverage)
+	count := func(groups map[string]*coverage, name string, tracked bool) {
+		if groups[name] == nil {
+			groups[name] = &coverage{Name: name}
+		}
+		groups[name].Commits++
+		if tracked {
+			groups[name].Tracked++
+		}
+	}
+
+	var untracked []auditCommit
+	r.Bypasses = []auditCommit{}
+	for _, c := range commits {
+		if c.Bypass != "" {
+			r.Bypasses = append(r.Bypasses, c)
+		}
+		tracked := len(c.Keys) > 0
+		r.Total.Commits++
+		if tracked {
+			r.Total.Tracked++
+		} else {
+			untracked = append(untracked, c)
+		}
+
+		count(authors, c.Author, tracked)
+		count(months, c.Date.Format("2006-01"), tracked)
+		if len(c.Projects) == 0 {
+			count(projects, noProject, tracked)
+		}
+		for _, p := range c.Projects {
+			count(projects, p, tracked)
+		}
+	}
+	r.Total.Name = "total"
+	r.Total.Coverage = percentage(r.Total.Tracked, r.Total.Commits)
+
+	r.Authors = sortedCoverage(authors, func(a, b coverage) bool {
+		if a.Commits != b.Commits {
+			return a.Commits > b.Commits
+		}
+		return a.Name < b.Name
+	})
+	r.Months = sortedCoverage(months, func(a, b coverage) bool { return a.Name < b.Name })
+	r.Projects = sortedCoverage(projects, func(a, b coverage) bool { return a.Name < b.Name })
+
+	sort.SliceStable(untracked, func(i, j int) bool { return untracked[i].Changes > untracked[j].Changes })
+	if top >= 0 && len(untracked) > top {
+		untracked = untracked[:top]
+	}
+	r.Untracked = untracked
+	if r.Untracked == nil {
+		r.Untracked = []auditCommit{}
+	}
+}
+
+func sortedCoverage(groups map[string]*coverage, less func(a, b coverage) bool) []coverage {
+	result := make([]coverage, 0, len(groups))
+	for _, g := range groups {
+		g.Coverage = percentage(g.Tracked, g.Commits)
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool { return less(result[i], result[j]) })
+	return result
+}
+
+// percentage returns part/total as a percentage rounded to one decimal
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
+
+// writeAuditTable prints the report as tables for people to read
+func writeAuditTable(out io.Writer, r auditReport) {
+	period := ""
+	if r.Since != "" {
+		period += " since " + r.Since
+	}
+	if r.Until != "" {
+		period += " until " + r.Until
+	}
+	fmt.Fprintf(out, "Ticket coverage%s: %d of %d commits (%.1f%%)\n", period, r.Total.Tracked, r.Total.Commits, r.Total.Coverage)
+
+	for _, section := range []struct {
+		title  string
+		groups []coverage
+	}{{"AUTHOR", r.Authors}, {"MONTH", r.Months}, {"PROJECT", r.Projects}} {
+		fmt.Fprintln(out)
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "%s\tCOMMITS\tTRACKED\tCOVERAGE\n", section.title)
+		for _, g := range section.groups {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%.1f%%\n", g.Name, g.Commits, g.Tracked, g.Coverage)
+		}
+		_ = w.Flush()
+	}
+
+	if len(r.Untracked) > 0 {
+		fmt.Fprintln(out, "\nLargest untracked commits:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, c := range r.Untracked {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d lines\t%s\n", c.SHA[:7], c.Date.Format(time.DateOnly), c.Author, c.Changes, c.Subject)
+		}
+		_ = w.Flush()
+	}
+
+	if len(r.Bypasses) > 0 {
+		fmt.Fprintln(out, "\nCommits that skipped jitt's checks:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, c := range r.Bypasses {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", c.SHA[:7], c.Date.Format(time.DateOnly), c.Author, c.Bypass, c.Subject)
+		}
+		_ = w.Flush()
+	}
+
+	if len(r.UnknownKeys) > 0 {
+		fmt.Fprintln(out, "\nKeys referencing unknown projects:")
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, k := range r.UnknownKeys {
+			fmt.Fprintf(w, "%s\t%s\n", k.Key, strings.Join(k.Commits, ", "))
+		}
+		_ = w.Flush()
+	}
+}
+
+// writeAuditCSV writes the coverage figures as CSV, one row per author, month
+// and project, followed by a row per commit that skipped jitt's checks; the
+// other commit lists are left to the table and JSON formats
+func writeAuditCSV(out io.Writer, r auditReport) error {
+	w := csv.NewWriter(out)
+	_ = w.Write([]string{"group", "name", "commits", "tracked", "coverage", "date", "author", "reason"})
+	row := func(group string, g coverage) {
+		_ = w.Write([]string{group, g.Name, strconv.Itoa(g.Commits), strconv.Itoa(g.Tracked),
+			strconv.FormatFloat(g.Coverage, 'f', 1, 64), "", "", ""})
+	}
+
+	row("total", r.Total)
+	for _, g := range r.Authors {
+		row("author", g)
+	}
+	for _, g := range r.Months {
+		row("month", g)
+	}
+	for _, g := range r.Projects {
+		row("project", g)
+	}
+	for _, c := range r.Bypasses {
+		_ = w.Write([]string{"bypass", c.SHA, "", "", "", c.Date.Format(time.DateOnly), c.Author, c.Bypass})
+	}
+
+	w.Flush()
+	return w.Error()
+}
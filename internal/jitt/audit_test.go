@@ -0,0 +1,146 @@
+package jitt
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/bbommarito/jitt/internal/git/gittest"
+)
+
+var _ = Describe("jitt audit command", func() {
+	var (
+		oldCwd string
+		repo   *gittest.Repo
+	)
+
+	runAudit := func(args ...string) *gexec.Session {
+		command := exec.Command(pathToJittBinary, append([]string{"audit"}, args...)...)
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		return session
+	}
+
+	BeforeEach(func() {
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+
+		repo = initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: OPS\n"+
+			"projects:\n  - paths: [\"web/**\"]\n    keys: [WEB]\n"), 0o600)).To(Succeed())
+
+		jane := gittest.Author("Jane Doe", "jane@example.com")
+		john := gittest.Author("John Roe", "john@example.com")
+		repo.File("old.txt", "1\n").Commit("OPS-1 Before the audit", jane, gittest.Date("2025-12-20T10:00:00Z"))
+		repo.File("web/app.js", "1\n2\n").Commit("WEB-1 Add app", jane, gittest.Date("2026-01-05T10:00:00Z"))
+		repo.File("web/app.js", "1\n2\n3\n4\n5\n").Commit("Grow the app", john, gittest.Date("2026-01-10T10:00:00Z"))
+		repo.File("ops.sh", "1\n").Commit("OPS-2 Add script", john, gittest.Date("2026-02-01T10:00:00Z"))
+		repo.File("ops.sh", "1\n2\n").Commit("tweak script", john, gittest.Date("2026-02-02T10:00:00Z"))
+		repo.File("notes.txt", "1\n").Commit("FOO-9 Mention another project", jane, gittest.Date("2026-02-03T10:00:00Z"))
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should print coverage tables, untracked commits and unknown keys", func() {
+		session := runAudit("--since", "2026-01-01")
+
+		Eventually(session).Should(gexec.Exit(0))
+		output := string(session.Out.Contents())
+		Expect(output).To(ContainSubstring("Ticket coverage since 2026-01-01: 2 of 5 commits (40.0%)"))
+		Expect(output).To(MatchRegexp(`John Roe\s+3\s+1\s+33.3%`))
+		Expect(output).To(MatchRegexp(`Jane Doe\s+2\s+1\s+50.0%`))
+		Expect(output).To(MatchRegexp(`2026-01\s+2\s+1\s+50.0%\n2026-02\s+3\s+1\s+33.3%`))
+		Expect(output).To(MatchRegexp(`OPS\s+3\s+1\s+33.3%\nWEB\s+2\s+1\s+50.0%`))
+		Expect(output).To(MatchRegexp(`Largest untracked commits:\n[0-9a-f]{7}\s+2026-01-10\s+John Roe\s+3 lines\s+Grow the app\n`))
+		Expect(output).To(MatchRegexp(`Keys referencing unknown projects:\nFOO-9\s+[0-9a-f]{7}`))
+		Expect(output).NotTo(ContainSubstring("Before the audit"))
+	})
+
+	It("should list the commits that skipped jitt's checks", func() {
+		repo.File("hotfix.sh", "1\n").Commit("Stop the bleeding\n\nJitt-Skip: prod is down",
+			gittest.Author("John Roe", "john@example.com"), gittest.Date("2026-02-04T10:00:00Z"))
+
+		session := runAudit("--since", "2026-01-01")
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(MatchRegexp(
+			`Commits that skipped jitt's checks:\n[0-9a-f]{7}\s+2026-02-04\s+John Roe\s+prod is down\s+Stop the bleeding\n`))
+
+		session = runAudit("--since", "2026-01-01", "--format", "json")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring(`"bypass": "prod is down"`))
+
+		session = runAudit("--since", "2026-01-01", "--format", "csv")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(MatchRegexp(`\nbypass,[0-9a-f]{40},,,,2026-02-04,John Roe,prod is down\n`))
+	})
+
+	It("should select and group commits by the same date, the author date", func() {
+		// Authored before the window, committed inside it, as after a rebase
+		repo.File("late.txt", "1\n").Commit("Rebased late", gittest.Author("John Roe", "john@example.com"),
+			gittest.Date("2026-01-02T10:00:00Z"), gittest.AuthorDate("2025-12-30T10:00:00Z"))
+
+		session := runAudit("--since", "2026-01-01", "--until", "2026-01-31")
+
+		Eventually(session).Should(gexec.Exit(0))
+		output := string(session.Out.Contents())
+		Expect(output).To(ContainSubstring("1 of 2 commits"))
+		Expect(output).NotTo(ContainSubstring("2025-12"))
+		Expect(output).NotTo(ContainSubstring("2026-02"))
+		Expect(output).NotTo(ContainSubstring("Rebased late"))
+	})
+
+	It("should write CSV", func() {
+		session := runAudit("--since", "2026-01-01", "--format", "csv")
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(strings.Split(strings.TrimSpace(string(session.Out.Contents())), "\n")).To(Equal([]string{
+			"group,name,commits,tracked,coverage,date,author,reason",
+			"total,total,5,2,40.0,,,",
+			"author,John Roe,3,1,33.3,,,",
+			"author,Jane Doe,2,1,50.0,,,",
+			"month,2026-01,2,1,50.0,,,",
+			"month,2026-02,3,1,33.3,,,",
+			"project,OPS,3,1,33.3,,,",
+			"project,WEB,2,1,50.0,,,",
+		}))
+	})
+
+	It("should write JSON", func() {
+		session := runAudit("--format", "json", "--top", "1")
+
+		Eventually(session).Should(gexec.Exit(0))
+		var report auditReport
+		Expect(json.Unmarshal(session.Out.Contents(), &report)).To(Succeed())
+		Expect(report.Total).To(Equal(coverage{Name: "total", Commits: 6, Tracked: 3, Coverage: 50}))
+		Expect(report.Untracked).To(HaveLen(1))
+		Expect(report.Untracked[0].Subject).To(Equal("Grow the app"))
+		Expect(report.Untracked[0].Projects).To(Equal([]string{"WEB"}))
+		Expect(report.UnknownKeys).To(HaveLen(1))
+		Expect(report.UnknownKeys[0].Key).To(Equal("FOO-9"))
+	})
+
+	It("should ask Jira which projects exist with --jira", func() {
+		jira := newFakeJira()
+		defer jira.Close()
+		jira.addIssue("FOO-1", "To Do")
+		Expect(jira.writeConfig("")).To(Succeed())
+
+		session := runAudit("--jira", "--format", "json")
+
+		Eventually(session).Should(gexec.Exit(0))
+		var report auditReport
+		Expect(json.Unmarshal(session.Out.Contents(), &report)).To(Succeed())
+		Expect(report.UnknownKeys).To(ConsistOf(
+			HaveField("Key", "OPS-1"), HaveField("Key", "WEB-1"), HaveField("Key", "OPS-2")))
+	})
+})
@@ -0,0 +1,103 @@
+package jitt
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bbommarito/jitt/pkg/config"
+)
+
+// vendorBase fetches the base config of a git source at its pinned ref and
+// writes it to .jitt/base.yaml next to the .jitt.yaml in dir, so loading the
+// configuration never needs the network. It returns the commit it came from.
+func vendorBase(dir string, ext config.ExtendsConfig) (string, error) {
+	ref := ext.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	file := ext.Path
+	if file == "" {
+		file = config.DefaultBasePath
+	}
+
+	tmp, err := os.MkdirTemp("", "jitt-base-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+
+	if _, err := runGit("init", "-q", "--bare", tmp); err != nil {
+		return "", err
+	}
+	env := []string{"GIT_DIR=" + tmp}
+	if _, err := runGitInput(env, "", "fetch", "-q", "--depth", "1", ext.Source, ref); err != nil {
+		return "", networkError("fetching %s from %s: %v", ref, ext.Source, err)
+	}
+	sha, err := runGitInput(env, "", "rev-parse", "FETCH_HEAD")
+	if err != nil {
+		return "", err
+	}
+	content, err := runGitInput(env, "", "show", "FETCH_HEAD:"+file)
+	if err != nil {
+		return "", configError("reading %s at %s: %v", file, ref, err)
+	}
+	if err := config.CheckBase([]byte(content)); err != nil {
+		return "", configError("%s at %s is not a valid jitt config: %v", file, ref, err)
+	}
+
+	vendorDir := filepath.Join(dir, config.VendorDir)
+	if err := os.MkdirAll(vendorDir, 0o755); err != nil {
+		return "", err
+	}
+	header := fmt.Sprintf("# Vendored by jitt from %s (%s at %s, commit %s).\n"+
+		"# Do not edit - change the source and run 'jitt config update-base'.\n", ext.Source, file, ref, sha)
+	if err := os.WriteFile(filepath.Join(vendorDir, config.BaseFileName), []byte(header+content+"\n"), 0o644); err != nil {
+		return "", err
+	}
+	return sha, nil
+}
+
+// updateBase handles 'jitt config update-base [--ref <ref>]': it re-vendors
+// the base config of a git source, optionally moving its pin to a new ref
+func updateBase(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("config update-base", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	ref := fs.String("ref", "", "pin the base to this branch, tag or commit first")
+	if _, err := parseFlags(fs, args); err != nil {
+		return flagError(err)
+	}
+
+	ext, err := config.ReadExtends(".")
+	if err != nil {
+		return configError("Error loading config: %v", err)
+	}
+	switch {
+	case ext.Source == "":
+		return configError(".jitt.yaml does not extend a base config (set extends.source)")
+	case !ext.IsGit():
+		fmt.Fprintf(stdio.Out, "%s is a local file, read directly - nothing to update\n", ext.Source)
+		return nil
+	}
+
+	// Move the pin only once the new ref is vendored, so a ref that can't be
+	// fetched leaves .jitt.yaml pointing at the base that is in .jitt/
+	pinned := ext.Ref
+	if *ref != "" {
+		ext.Ref = *ref
+	}
+
+	sha, err := vendorBase(".", ext)
+	if err != nil {
+		return &Error{Code: ExitCode(err), Err: fmt.Errorf("Error updating base config: %w", err)}
+	}
+	if ext.Ref != pinned {
+		if err := config.Update("extends.ref", ext.Ref); err != nil {
+			return failure("Error updating config: %v", err)
+		}
+	}
+	fmt.Fprintf(stdio.Out, "Vendored base config from %s at %s into %s\n", ext.Source, sha[:7],
+		filepath.ToSlash(filepath.Join(config.VendorDir, config.BaseFileName)))
+	return nil
+}
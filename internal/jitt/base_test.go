@@ -0,0 +1,128 @@
+package jitt
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("shared base configs", func() {
+	var (
+		oldCwd string
+		shared string
+	)
+
+	runJitt := func(args ...string) *gexec.Session {
+		session, err := gexec.Start(exec.Command(pathToJittBinary, args...), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		return session
+	}
+
+	// publish commits a .jitt.yaml to the shared repository and tags it
+	publish := func(tag, content string) {
+		Expect(os.WriteFile(filepath.Join(shared, ".jitt.yaml"), []byte(content), 0o600)).To(Succeed())
+		for _, args := range [][]string{
+			{"add", ".jitt.yaml"}, {"commit", "-q", "-m", "Release " + tag}, {"tag", tag},
+		} {
+			out, err := exec.Command("git", append([]string{"-C", shared}, args...)...).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+		}
+	}
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+		shared = filepath.Join(tmpDir, "jitt-config")
+		Expect(os.Mkdir(shared, 0o755)).To(Succeed())
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+
+		Expect(os.Chdir(shared)).To(Succeed())
+		initGitRepo()
+		publish("v1", "jira:\n  project: ORG\nlint:\n  subject_period:\n    severity: error\n")
+		publish("v2", "jira:\n  project: ORG\nlint:\n  subject_period:\n    severity: off\n")
+
+		repo := filepath.Join(tmpDir, "repo")
+		Expect(os.Mkdir(repo, 0o755)).To(Succeed())
+		Expect(os.Chdir(repo)).To(Succeed())
+		initGitRepo()
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should vendor a pinned base from a git repository and apply it", func() {
+		session := runJitt("init", "--from", "../jitt-config/.git", "--from-ref", "v1")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(MatchRegexp(`Vendored base config from \.\./jitt-config/\.git at [0-9a-f]{7} into \.jitt/base\.yaml`))
+
+		content, err := os.ReadFile(".jitt.yaml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("source: ../jitt-config/.git"))
+		Expect(string(content)).To(ContainSubstring("ref: v1"))
+		Expect(string(content)).NotTo(ContainSubstring("project"))
+
+		vendored, err := os.ReadFile(filepath.Join(".jitt", "base.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(vendored)).To(HavePrefix("# Vendored by jitt from ../jitt-config/.git (.jitt.yaml at v1, commit "))
+
+		session = runValidateMessage("ORG-1 Add login.")
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("[subject-period]"))
+	})
+
+	It("should move the pin with config update-base --ref", func() {
+		Eventually(runJitt("init", "ABC", "--from", "../jitt-config/.git", "--from-ref", "v1")).Should(gexec.Exit(0))
+
+		session := runJitt("config", "update-base", "--ref", "v2")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("Vendored base config"))
+		Expect(os.ReadFile(".jitt.yaml")).To(ContainSubstring("ref: v2"))
+
+		Eventually(runValidateMessage("ABC-1 Add login.")).Should(gexec.Exit(0))
+	})
+
+	It("should keep the pin and the vendored base when the new ref can't be fetched", func() {
+		Eventually(runJitt("init", "ABC", "--from", "../jitt-config/.git", "--from-ref", "v1")).Should(gexec.Exit(0))
+		vendored, err := os.ReadFile(filepath.Join(".jitt", "base.yaml"))
+		Expect(err).NotTo(HaveOccurred())
+
+		session := runJitt("config", "update-base", "--ref", "v9")
+		Eventually(session).Should(gexec.Exit(ExitNetwork))
+		Expect(os.ReadFile(".jitt.yaml")).To(ContainSubstring("ref: v1"))
+		Expect(os.ReadFile(filepath.Join(".jitt", "base.yaml"))).To(Equal(vendored))
+
+		Eventually(runValidateMessage("ABC-1 Add login.")).Should(gexec.Exit(1))
+	})
+
+	It("should read local base files directly", func() {
+		Expect(os.WriteFile("../base.yaml", []byte("jira:\n  project: ORG\n  url: https://jira.example.com\n"), 0o600)).To(Succeed())
+
+		Eventually(runJitt("init", "--from", "../base.yaml")).Should(gexec.Exit(0))
+		Expect(".jitt").NotTo(BeADirectory())
+
+		session := runJitt("config", "--explain", ".")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(MatchRegexp(`jira\.url = https://jira\.example\.com\s+\(\.jitt\.yaml \(extends \.\./base\.yaml\)\)`))
+
+		session = runJitt("config", "update-base")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("is a local file, read directly"))
+	})
+
+	It("should refuse bases that can't be fetched", func() {
+		session := runJitt("init", "--from", "../jitt-config/.git", "--from-ref", "v9")
+
+		Eventually(session).Should(gexec.Exit(ExitNetwork))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("fetching v9 from ../jitt-config/.git"))
+		Expect(".jitt.yaml").NotTo(BeAnExistingFile())
+		Expect(strings.TrimSpace(git("status", "--porcelain"))).To(BeEmpty())
+	})
+})
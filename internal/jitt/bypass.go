@@ -0,0 +1,22 @@
+package jitt
+
+import (
+	"fmt"
+
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// bypassEnv names the environment variable that lets a commit through the
+// commit-msg hook, its value recorded in the commit as the reason
+const bypassEnv = "JITT_SKIP"
+
+// bypassProblem reports a bypass attempted where it isn't allowed
+func bypassProblem(branch string) lib.Problem {
+	where := "here"
+	if branch != "" {
+		where = "on branch " + branch
+	}
+	return lib.Problem{Rule: lib.RuleBypass, Severity: config.SeverityError,
+		Message: fmt.Sprintf("skipping jitt's checks is not allowed %s", where)}
+}
@@ -0,0 +1,113 @@
+package jitt
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bbommarito/jitt/pkg/config"
+)
+
+// HandleChangelog handles the 'jitt changelog' command
+func HandleChangelog(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("changelog", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	summaries := fs.Bool("summaries", false, "include ticket summaries from Jira")
+	output := fs.String("output", "", "write the changelog to a file instead of stdout")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagError(err)
+	}
+
+	if len(positional) > 1 {
+		return usageError("Usage: jitt changelog [<range>] [--summaries] [--output <file>]")
+	}
+
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+
+	rangeSpec := "HEAD"
+	if len(positional) == 1 {
+		rangeSpec = positional[0]
+	} else if tag, err := runGit("describe", "--tags", "--abbrev=0"); err == nil {
+		rangeSpec = tag + "..HEAD"
+	}
+
+	commits, err := ticketCommits(cfg, "--reverse", rangeSpec)
+	if err != nil {
+		return failure("Error reading commits: %v", err)
+	}
+
+	// Group by ticket, in order of first appearance; a commit referencing
+	// several tickets is listed under each
+	var keys []string
+	byKey := make(map[string][]ticketCommit)
+	var other []ticketCommit
+	for _, c := range commits {
+		if len(c.Keys) == 0 {
+			other = append(other, c)
+			continue
+		}
+		for _, key := range c.Keys {
+			if _, seen := byKey[key]; !seen {
+				keys = append(keys, key)
+			}
+			byKey[key] = append(byKey[key], c)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "## Changes (%s)\n", rangeSpec)
+	if len(commits) == 0 {
+		b.WriteString("\n_No commits._\n")
+	}
+	for _, key := range keys {
+		heading := key
+		if *summaries {
+			if summary := ticketSummary(stdio.Err, cfg, key); summary != "" {
+				heading += ": " + summary
+			}
+		}
+		fmt.Fprintf(&b, "\n### %s\n\n", heading)
+		writeChangelogCommits(&b, byKey[key])
+	}
+	if len(other) > 0 {
+		b.WriteString("\n### Other changes\n\n")
+		writeChangelogCommits(&b, other)
+	}
+
+	if *output == "" {
+		fmt.Fprint(stdio.Out, b.String())
+		return nil
+	}
+	if err := os.WriteFile(*output, []byte(b.String()), 0o644); err != nil {
+		return failure("Error writing %s: %v", *output, err)
+	}
+	fmt.Fprintf(stdio.Out, "Wrote changelog to %s\n", *output)
+	return nil
+}
+
+func writeChangelogCommits(b *strings.Builder, commits []ticketCommit) {
+	for _, c := range commits {
+		fmt.Fprintf(b, "- %s (%s)\n", c.Subject, c.SHA[:7])
+	}
+}
+
+// ticketSummary fetches a ticket's summary, or returns "" when Jira can't tell
+func ticketSummary(w io.Writer, cfg *config.Config, key string) string {
+	client, err := newJiraClient(cfg)
+	if err != nil {
+		return ""
+	}
+	issue, err := client.Issue(context.Background(), key)
+	if err != nil {
+		fmt.Fprintf(w, "jitt: could not fetch %s: %v\n", key, err)
+		return ""
+	}
+	return issue.Fields.Summary
+}
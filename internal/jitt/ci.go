@@ -0,0 +1,202 @@
+package jitt
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	gitrepo "github.com/bbommarito/jitt/internal/git"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// Report formats understood by 'jitt ci check'
+const (
+	reportText   = "text"
+	reportGitHub = "github"
+	reportGitLab = "gitlab"
+	reportJUnit  = "junit"
+)
+
+// ciEnvironment is what 'jitt ci check' works out about the CI run
+type ciEnvironment struct {
+	Provider string
+	Range    string
+	Branch   string
+}
+
+// githubEvent holds the parts of a GitHub Actions event payload jitt needs
+type githubEvent struct {
+	Before      string `json:"before"`
+	After       string `json:"after"`
+	PullRequest *struct {
+		Base struct {
+			SHA string `json:"sha"`
+		} `json:"base"`
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	} `json:"pull_request"`
+}
+
+// HandleCI handles the 'jitt ci' command
+func HandleCI(stdio IO, args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return usageError("Usage: jitt ci check [--range <range>] [--branch <name>] [--config-rev <rev>] [--format text|github|gitlab|junit] [--report-file <file>]")
+	}
+
+	fs := flag.NewFlagSet("ci check", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	rangeFlag := fs.String("range", "", "commits to check (default: detected from the CI environment)")
+	branchFlag := fs.String("branch", "", "branch name to check (default: detected from the CI environment)")
+	format := fs.String("format", "", "output format: text, github, gitlab or junit (default: the CI's native format)")
+	reportFile := fs.String("report-file", "", "where gitlab and junit reports are written")
+	configRev := fs.String("config-rev", "", "apply the .jitt.yaml files committed at this revision, such as the target branch")
+	if _, err := parseFlags(fs, args[1:]); err != nil {
+		return flagError(err)
+	}
+
+	cfg, scopes, err := loadCheckConfig(*configRev)
+	if err != nil {
+		return err
+	}
+
+	env := detectCI(os.Getenv, cfg.Workflow.MainBranch)
+	if *rangeFlag != "" {
+		env.Range = *rangeFlag
+	}
+	if *branchFlag != "" {
+		env.Branch = *branchFlag
+	}
+	if env.Branch == "" {
+		env.Branch, _ = currentBranch()
+	}
+	if *format == "" {
+		*format = defaultReportFormat(env.Provider)
+	}
+
+	fmt.Fprintf(stdio.Out, "jitt: checking %s on branch %q (%s)\n", env.Range, env.Branch, env.Provider)
+	results, err := checkCommits(context.Background(), stdio.Err, cfg, scopes, env.Branch, env.Range)
+	if err != nil {
+		return failure("Error reading commits in %s: %v\n"+
+			"Shallow clones miss the commits to check - fetch the full history (e.g. fetch-depth: 0)", env.Range, err)
+	}
+	if env.Branch != "" {
+		results = append(results, checkResult{Name: "branch " + env.Branch, Problems: lib.CheckBranch(cfg, env.Branch)})
+	}
+
+	switch *format {
+	case reportText:
+		writeTextReport(stdio.Out, results)
+	case reportGitHub:
+		writeGitHubAnnotations(stdio.Out, results)
+		writeTextReport(stdio.Out, results)
+	case reportGitLab:
+		err = writeReportFile(stdio.Out, *reportFile, "gl-code-quality-report.json", func(w io.Writer) error {
+			return writeGitLabCodeQuality(w, results)
+		})
+		writeTextReport(stdio.Out, results)
+	case reportJUnit:
+		err = writeReportFile(stdio.Out, *reportFile, "jitt-junit.xml", func(w io.Writer) error {
+			return writeJUnit(w, results)
+		})
+		writeTextReport(stdio.Out, results)
+	default:
+		return usageError("Unknown format: %s (use text, github, gitlab or junit)", *format)
+	}
+	if err != nil {
+		return failure("Error writing report: %v", err)
+	}
+
+	if errors, _ := countProblems(results); errors > 0 {
+		return &Error{Code: ExitFailure}
+	}
+	return nil
+}
+
+// detectCI works out the provider, commit range and branch of a CI run from
+// its environment variables, falling back to <main branch>..HEAD
+func detectCI(getenv func(string) string, mainBranch string) ciEnvironment {
+	fallbackBase := "origin/" + mainBranch
+
+	switch {
+	case getenv("GITHUB_ACTIONS") == "true":
+		env := ciEnvironment{Provider: "github", Range: fallbackBase + "..HEAD", Branch: getenv("GITHUB_HEAD_REF")}
+		if env.Branch == "" {
+			env.Branch = getenv("GITHUB_REF_NAME")
+		}
+
+		var event githubEvent
+		if data, err := os.ReadFile(getenv("GITHUB_EVENT_PATH")); err == nil && json.Unmarshal(data, &event) == nil {
+			switch {
+			case event.PullRequest != nil && event.PullRequest.Base.SHA != "":
+				env.Range = event.PullRequest.Base.SHA + ".." + event.PullRequest.Head.SHA
+			case event.After != "" && !gitrepo.IsZeroSHA(event.Before):
+				env.Range = event.Before + ".." + event.After
+			case event.After != "" && !gitrepo.IsZeroSHA(event.After):
+				env.Range = fallbackBase + ".." + event.After
+			}
+		}
+		return env
+
+	case getenv("GITLAB_CI") == "true":
+		env := ciEnvironment{Provider: "gitlab", Range: fallbackBase + "..HEAD"}
+		head := getenv("CI_COMMIT_SHA")
+		if head == "" {
+			head = "HEAD"
+		}
+
+		if base := getenv("CI_MERGE_REQUEST_DIFF_BASE_SHA"); base != "" {
+			env.Range = base + ".." + head
+			env.Branch = getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME")
+			return env
+		}
+		if before := getenv("CI_COMMIT_BEFORE_SHA"); before != "" && !gitrepo.IsZeroSHA(before) {
+			env.Range = before + ".." + head
+		} else {
+			env.Range = fallbackBase + ".." + head
+		}
+		env.Branch = getenv("CI_COMMIT_BRANCH")
+		return env
+
+	default:
+		return ciEnvironment{Provider: "generic", Range: fallbackBase + "..HEAD", Branch: getenv("BRANCH_NAME")}
+	}
+}
+
+func defaultReportFormat(provider string) string {
+	switch provider {
+	case "github":
+		return reportGitHub
+	case "gitlab":
+		return reportGitLab
+	default:
+		return reportText
+	}
+}
+
+// writeReportFile writes a report to path (or defaultPath), or to out for "-"
+func writeReportFile(out io.Writer, path, defaultPath string, write func(io.Writer) error) error {
+	if path == "" {
+		path = defaultPath
+	}
+	if path == "-" {
+		return write(out)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Wrote report to %s\n", path)
+	return nil
+}
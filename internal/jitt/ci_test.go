@@ -0,0 +1,178 @@
+package jitt
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("detectCI", func() {
+	detect := func(vars map[string]string) ciEnvironment {
+		return detectCI(func(name string) string { return vars[name] }, "main")
+	}
+
+	writeEvent := func(event string) string {
+		path := filepath.Join(GinkgoT().TempDir(), "event.json")
+		Expect(os.WriteFile(path, []byte(event), 0o600)).To(Succeed())
+		return path
+	}
+
+	It("should use the pull request's base and head on GitHub", func() {
+		env := detect(map[string]string{
+			"GITHUB_ACTIONS":    "true",
+			"GITHUB_HEAD_REF":   "feature/ABC-1-login",
+			"GITHUB_REF_NAME":   "12/merge",
+			"GITHUB_EVENT_PATH": writeEvent(`{"pull_request":{"base":{"sha":"b4se"},"head":{"sha":"he4d"}}}`),
+		})
+		Expect(env).To(Equal(ciEnvironment{Provider: "github", Range: "b4se..he4d", Branch: "feature/ABC-1-login"}))
+	})
+
+	It("should use the pushed range on GitHub, or the main branch for new branches", func() {
+		env := detect(map[string]string{
+			"GITHUB_ACTIONS":    "true",
+			"GITHUB_REF_NAME":   "feature/ABC-1-login",
+			"GITHUB_EVENT_PATH": writeEvent(`{"before":"0000000000000000000000000000000000000000","after":"4fter"}`),
+		})
+		Expect(env).To(Equal(ciEnvironment{Provider: "github", Range: "origin/main..4fter", Branch: "feature/ABC-1-login"}))
+
+		env = detect(map[string]string{
+			"GITHUB_ACTIONS":    "true",
+			"GITHUB_EVENT_PATH": writeEvent(`{"before":"bef0re","after":"4fter"}`),
+		})
+		Expect(env.Range).To(Equal("bef0re..4fter"))
+	})
+
+	It("should use the merge request's diff base on GitLab", func() {
+		env := detect(map[string]string{
+			"GITLAB_CI":                           "true",
+			"CI_COMMIT_SHA":                       "he4d",
+			"CI_MERGE_REQUEST_DIFF_BASE_SHA":      "b4se",
+			"CI_MERGE_REQUEST_SOURCE_BRANCH_NAME": "feature/ABC-1-login",
+		})
+		Expect(env).To(Equal(ciEnvironment{Provider: "gitlab", Range: "b4se..he4d", Branch: "feature/ABC-1-login"}))
+	})
+
+	It("should use the pushed range on GitLab", func() {
+		env := detect(map[string]string{
+			"GITLAB_CI":            "true",
+			"CI_COMMIT_SHA":        "he4d",
+			"CI_COMMIT_BEFORE_SHA": "bef0re",
+			"CI_COMMIT_BRANCH":     "main",
+		})
+		Expect(env).To(Equal(ciEnvironment{Provider: "gitlab", Range: "bef0re..he4d", Branch: "main"}))
+	})
+
+	It("should fall back to the main branch elsewhere", func() {
+		Expect(detect(map[string]string{"BRANCH_NAME": "feature/x"})).To(Equal(
+			ciEnvironment{Provider: "generic", Range: "origin/main..HEAD", Branch: "feature/x"}))
+	})
+})
+
+var _ = Describe("jitt ci check command", func() {
+	var (
+		tmpDir string
+		oldCwd string
+	)
+
+	runCICheck := func(env []string, args ...string) *gexec.Session {
+		command := exec.Command(pathToJittBinary, append([]string{"ci", "check"}, args...)...)
+		command.Env = append(os.Environ(), "GITHUB_ACTIONS=", "GITLAB_CI=", "BRANCH_NAME=")
+		command.Env = append(command.Env, env...)
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		return session
+	}
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"+
+			"branch:\n  pattern: ^feature/[A-Z]+-[0-9]+-\n"+
+			"lint:\n  subject_period:\n    severity: warning\n"), 0o600)).To(Succeed())
+		commit("ABC-1 Initial commit")
+		git("checkout", "-q", "-b", "feature/ABC-2-login")
+		commit("ABC-2 Add login form.")
+		commit("Style the login form")
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should report problems as text on generic runners", func() {
+		session := runCICheck(nil, "--range", "main..HEAD")
+
+		Eventually(session).Should(gexec.Exit(1))
+		output := string(session.Out.Contents())
+		Expect(output).To(ContainSubstring(`jitt: checking main..HEAD on branch "feature/ABC-2-login" (generic)`))
+		Expect(output).To(ContainSubstring("subject ends with a period [subject-period]"))
+		Expect(output).To(MatchRegexp(`[0-9a-f]{7} Style the login form\n  ❌ commit message does not reference a Jira ticket`))
+		Expect(output).To(ContainSubstring("Checked 3 items: 1 errors, 1 warnings"))
+	})
+
+	It("should pass when every commit and the branch are fine", func() {
+		Eventually(runCICheck(nil, "--range", "main..HEAD~1")).Should(gexec.Exit(0))
+	})
+
+	It("should check the branch name against branch.pattern", func() {
+		session := runCICheck(nil, "--range", "main..HEAD~1", "--branch", "login-stuff")
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Out.Contents())).To(ContainSubstring(
+			`branch "login-stuff" does not match branch.pattern ^feature/[A-Z]+-[0-9]+- [branch-pattern]`))
+	})
+
+	It("should emit GitHub annotations for the pull request range", func() {
+		base := git("rev-parse", "main")
+		head := git("rev-parse", "HEAD")
+		event := filepath.Join(tmpDir, "event.json")
+		Expect(os.WriteFile(event, []byte(`{"pull_request":{"base":{"sha":"`+base+`"},"head":{"sha":"`+head+`"}}}`), 0o600)).To(Succeed())
+
+		session := runCICheck([]string{"GITHUB_ACTIONS=true", "GITHUB_EVENT_PATH=" + event, "GITHUB_HEAD_REF=feature/ABC-2-login"})
+
+		Eventually(session).Should(gexec.Exit(1))
+		output := string(session.Out.Contents())
+		Expect(output).To(MatchRegexp(`(?m)^::error title=jitt ticket-reference::[0-9a-f]{7} Style the login form: commit message does not reference`))
+		Expect(output).To(MatchRegexp(`(?m)^::warning title=jitt subject-period::`))
+	})
+
+	It("should write a GitLab code quality report", func() {
+		session := runCICheck([]string{"GITLAB_CI=true", "CI_MERGE_REQUEST_DIFF_BASE_SHA=" + git("rev-parse", "main"),
+			"CI_COMMIT_SHA=" + git("rev-parse", "HEAD"), "CI_MERGE_REQUEST_SOURCE_BRANCH_NAME=feature/ABC-2-login"})
+
+		Eventually(session).Should(gexec.Exit(1))
+		data, err := os.ReadFile("gl-code-quality-report.json")
+		Expect(err).NotTo(HaveOccurred())
+
+		var issues []map[string]any
+		Expect(json.Unmarshal(data, &issues)).To(Succeed())
+		Expect(issues).To(HaveLen(2))
+		Expect(issues[0]).To(HaveKeyWithValue("check_name", "subject-period"))
+		Expect(issues[0]).To(HaveKeyWithValue("severity", "minor"))
+		Expect(issues[1]).To(HaveKeyWithValue("check_name", "ticket-reference"))
+		Expect(issues[1]).To(HaveKeyWithValue("severity", "major"))
+	})
+
+	It("should write a JUnit report", func() {
+		session := runCICheck(nil, "--range", "main..HEAD", "--format", "junit", "--report-file", "report.xml")
+
+		Eventually(session).Should(gexec.Exit(1))
+		data, err := os.ReadFile("report.xml")
+		Expect(err).NotTo(HaveOccurred())
+		report := string(data)
+		Expect(report).To(ContainSubstring(`<testsuite name="jitt" tests="3" failures="1">`))
+		Expect(report).To(ContainSubstring(`<testcase classname="jitt.branch" name="branch feature/ABC-2-login"></testcase>`))
+		Expect(report).To(MatchRegexp(`<failure message="commit message does not reference a Jira ticket \(e.g. ABC-123\)" type="ticket-reference">`))
+	})
+})
@@ -2,71 +2,111 @@ package jitt
 
 import (
 	"fmt"
-	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
-	"github.com/bbommarito/jitt/internal/config"
+	"github.com/bbommarito/jitt/pkg/config"
 )
 
 // HandleConfig handles the 'jitt config' command
-func HandleConfig(args []string) {
+func HandleConfig(stdio IO, args []string) error {
 	// Check if we're in a Git repository
 	if !isGitRepo() {
-		fmt.Fprintln(os.Stderr, "Not inside a Git repo.")
-		osExit(1)
-		return
+		return configError("Not inside a Git repo.")
 	}
 
 	// Check if config file exists
 	if !HasConfigFile() {
-		fmt.Fprintln(os.Stderr, ".jitt.yaml file not found - run 'jitt init' first")
-		osExit(1)
-		return
+		return configError(".jitt.yaml file not found - run 'jitt init' first")
 	}
 
 	// If no args, show all config
 	if len(args) == 0 {
 		cfg, err := config.Load()
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-			osExit(1)
-			return
+			return configError("Error loading config: %v", err)
 		}
 
-		fmt.Println("Current configuration:")
-		fmt.Printf("  jira.project = %s\n", cfg.Jira.Project)
-		return
+		fmt.Fprintln(stdio.Out, "Current configuration:")
+		fmt.Fprintf(stdio.Out, "  jira.project = %s\n", cfg.Jira.Project)
+		return nil
 	}
 
 	// Handle specific config keys
 	switch args[0] {
+	case "--explain":
+		if len(args) != 2 {
+			return usageError("Usage: jitt config --explain <path>")
+		}
+		return explainConfig(stdio, args[1])
+	case "update-base":
+		return updateBase(stdio, args[1:])
 	case "project":
 		if len(args) == 1 {
 			// Show current project
 			cfg, err := config.Load()
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
-				osExit(1)
-				return
+				return configError("Error loading config: %v", err)
 			}
 			if cfg.Jira.Project == "" {
-				fmt.Println("No project configured")
+				fmt.Fprintln(stdio.Out, "No project configured")
 			} else {
-				fmt.Printf("jira.project = %s\n", cfg.Jira.Project)
-			}
-		} else {
-			// Set project
-			newProject := args[1]
-			err := config.Update("jira.project", newProject)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error updating config: %v\n", err)
-				osExit(1)
-				return
+				fmt.Fprintf(stdio.Out, "jira.project = %s\n", cfg.Jira.Project)
 			}
-			fmt.Printf("Set jira.project = %s\n", newProject)
+			return nil
+		}
+
+		// Set project
+		newProject := args[1]
+		if err := config.Update("jira.project", newProject); err != nil {
+			return failure("Error updating config: %v", err)
 		}
+		fmt.Fprintf(stdio.Out, "Set jira.project = %s\n", newProject)
+		return nil
 	default:
-		fmt.Fprintf(os.Stderr, "Unknown config key: %s\n", args[0])
-		fmt.Fprintln(os.Stderr, "Available keys: project")
-		osExit(1)
+		return usageError("Unknown config key: %s\nAvailable keys: project", args[0])
+	}
+}
+
+// explainConfig prints the effective configuration for a path, showing which
+// .jitt.yaml file each value comes from
+func explainConfig(stdio IO, target string) error {
+	// Resolve nested configs from the top of the working tree, as validation
+	// does, and relative paths from the working directory
+	root, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return configError("Error: %v", err)
+	}
+	prefix, err := runGit("rev-parse", "--show-prefix")
+	if err != nil {
+		return configError("Error: %v", err)
+	}
+
+	abs := target
+	if !filepath.IsAbs(abs) {
+		abs = filepath.Join(root, filepath.FromSlash(prefix), target)
+	}
+	target, err = filepath.Rel(root, abs)
+	if err != nil || target == ".." || strings.HasPrefix(target, ".."+string(filepath.Separator)) {
+		return usageError("Error: %s is outside the repository", abs)
+	}
+
+	settings, files, err := config.Explain(root, filepath.ToSlash(target))
+	if err != nil {
+		return configError("Error loading config: %v", err)
+	}
+
+	fmt.Fprintf(stdio.Out, "Configuration for %s:\n", filepath.ToSlash(target))
+	fmt.Fprintln(stdio.Out, "  files applied (outermost first):")
+	for _, file := range files {
+		fmt.Fprintf(stdio.Out, "    %s\n", file)
+	}
+	fmt.Fprintln(stdio.Out)
+
+	w := tabwriter.NewWriter(stdio.Out, 0, 0, 2, ' ', 0)
+	for _, s := range settings {
+		fmt.Fprintf(w, "  %s = %v\t(%s)\n", s.Key, s.Value, s.Source)
 	}
+	return w.Flush()
 }
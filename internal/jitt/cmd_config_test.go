@@ -35,14 +35,14 @@ var _ = Describe("jitt config command", func() {
 			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(session).Should(gexec.Exit(1))
+			Eventually(session).Should(gexec.Exit(ExitConfig))
 			Expect(string(session.Err.Contents())).To(ContainSubstring("Not inside a Git repo"))
 		})
 	})
 
 	Context("inside a Git repository", func() {
 		BeforeEach(func() {
-			Expect(os.Mkdir(filepath.Join(tmpDir, ".git"), 0o755)).To(Succeed())
+			initGitRepo()
 		})
 
 		Context("with no .jitt.yaml file", func() {
@@ -51,7 +51,7 @@ var _ = Describe("jitt config command", func() {
 				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
 				Expect(err).NotTo(HaveOccurred())
 
-				Eventually(session).Should(gexec.Exit(1))
+				Eventually(session).Should(gexec.Exit(ExitConfig))
 				Expect(string(session.Err.Contents())).To(ContainSubstring(".jitt.yaml file not found"))
 				Expect(string(session.Err.Contents())).To(ContainSubstring("run 'jitt init' first"))
 			})
@@ -103,13 +103,56 @@ var _ = Describe("jitt config command", func() {
 				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
 				Expect(err).NotTo(HaveOccurred())
 
-				Eventually(session).Should(gexec.Exit(1))
+				Eventually(session).Should(gexec.Exit(ExitUsage))
 				output := string(session.Err.Contents())
 				Expect(output).To(ContainSubstring("Unknown config key: unknown"))
 				Expect(output).To(ContainSubstring("Available keys: project"))
 			})
 		})
 
+		Context("with nested .jitt.yaml files", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n  url: https://jira.example.com\n"), 0o600)).To(Succeed())
+				Expect(os.MkdirAll("services/billing", 0o755)).To(Succeed())
+				Expect(os.WriteFile("services/billing/.jitt.yaml", []byte("jira:\n  project: BILL\n"), 0o600)).To(Succeed())
+			})
+
+			It("should explain where each setting of a path comes from", func() {
+				command := exec.Command(pathToJittBinary, "config", "--explain", "services/billing/invoice.go")
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session).Should(gexec.Exit(0))
+				output := string(session.Out.Contents())
+				Expect(output).To(ContainSubstring("Configuration for services/billing/invoice.go:"))
+				Expect(output).To(MatchRegexp(`jira.project = BILL\s+\(services/billing/.jitt.yaml\)`))
+				Expect(output).To(MatchRegexp(`jira.url = https://jira.example.com\s+\(.jitt.yaml\)`))
+				Expect(output).To(MatchRegexp(`workflow.main_branch = main\s+\(default\)`))
+			})
+
+			It("should resolve from the top of the repository when run in a subdirectory", func() {
+				command := exec.Command(pathToJittBinary, "config", "--explain", "invoice.go")
+				command.Dir = filepath.Join("services", "billing")
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session).Should(gexec.Exit(0))
+				output := string(session.Out.Contents())
+				Expect(output).To(ContainSubstring("Configuration for services/billing/invoice.go:"))
+				Expect(output).To(MatchRegexp(`jira.project = BILL\s+\(services/billing/.jitt.yaml\)`))
+				Expect(output).To(MatchRegexp(`jira.url = https://jira.example.com\s+\(.jitt.yaml\)`))
+			})
+
+			It("should require a path", func() {
+				command := exec.Command(pathToJittBinary, "config", "--explain")
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session).Should(gexec.Exit(ExitUsage))
+				Expect(string(session.Err.Contents())).To(ContainSubstring("Usage: jitt config --explain <path>"))
+			})
+		})
+
 		Context("with .jitt.yaml file but no project configured", func() {
 			BeforeEach(func() {
 				Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: \"\""), 0o600)).To(Succeed())
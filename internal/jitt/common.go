@@ -0,0 +1,112 @@
+package jitt
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bbommarito/jitt/internal/jira"
+	"github.com/bbommarito/jitt/pkg/config"
+)
+
+// parseFlags parses args with fs, allowing flags and positional arguments to
+// be mixed (`jitt transition ABC-1 Done --dry-run`). It returns the positional
+// arguments in order.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// prompt prints question to out and reads one trimmed line of input; it
+// reports false once the input is exhausted
+func prompt(in *bufio.Reader, out io.Writer, question string) (string, bool) {
+	fmt.Fprint(out, question)
+	line, err := in.ReadString('\n')
+	if err != nil && line == "" {
+		fmt.Fprintln(out)
+		return "", false
+	}
+	return strings.TrimSpace(line), true
+}
+
+// splitList splits a comma- or space-separated answer into its items
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' })
+}
+
+// loadRepoConfig checks that we are in a Git repository with a .jitt.yaml and
+// loads it
+func loadRepoConfig() (*config.Config, error) {
+	if !isGitRepo() {
+		return nil, configError("Not inside a Git repo.")
+	}
+
+	if !HasConfigFile() {
+		return nil, configError(".jitt.yaml file not found - run 'jitt init' first")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, configError("Error loading config: %v", err)
+	}
+	return cfg, nil
+}
+
+// loadCheckConfig loads the configuration commits are checked against: the
+// working tree's, or with rev the .jitt.yaml files committed there, so a
+// branch cannot loosen the rules by editing its own
+func loadCheckConfig(rev string) (*config.Config, scopeFunc, error) {
+	if rev == "" {
+		cfg, err := loadRepoConfig()
+		return cfg, workingTreeScopes(), err
+	}
+
+	cfg, scopes, err := loadConfigAt(context.Background(), rev)
+	if err != nil {
+		return nil, nil, configError("Error loading config from %s: %v", rev, err)
+	}
+	return cfg, scopes, nil
+}
+
+// loadConfigAt loads the root .jitt.yaml committed at rev, along with the
+// scopes of the nested files committed beside it
+func loadConfigAt(ctx context.Context, rev string) (*config.Config, scopeFunc, error) {
+	tree := repo.Tree(ctx, rev)
+	cfg, _, err := config.LoadForFS(tree, ".")
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, treeScopes(tree), nil
+}
+
+// newJiraClient builds a Jira client from the config, taking credentials from
+// JITT_JIRA_USER and JITT_JIRA_TOKEN so they never end up in .jitt.yaml
+func newJiraClient(cfg *config.Config) (*jira.Client, error) {
+	if cfg.Jira.URL == "" {
+		return nil, fmt.Errorf("jira.url is not configured in .jitt.yaml")
+	}
+	return jira.NewClient(cfg.Jira.URL, os.Getenv("JITT_JIRA_USER"), os.Getenv("JITT_JIRA_TOKEN")), nil
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -3,26 +3,26 @@ package jitt
 import (
 	"fmt"
 
-	"github.com/bbommarito/jitt/internal/config"
+	"github.com/bbommarito/jitt/pkg/config"
 )
 
 // HandleDoctor handles the 'jitt doctor' command
-func HandleDoctor(args []string) {
+func HandleDoctor(stdio IO, args []string) error {
 	var issues []string
 	var warnings []string
 
 	// Check if we're in a Git repository
-	if !isGitRepo() {
+	if loc, err := locateRepo(); err != nil {
 		issues = append(issues, "❌ Not inside a Git repository")
 	} else {
-		fmt.Println("✅ Git repository found")
+		fmt.Fprintf(stdio.Out, "✅ Git repository found: %s\n", describeRepo(loc))
 	}
 
 	// Check if .jitt.yaml file exists
 	if !HasConfigFile() {
 		issues = append(issues, "❌ .jitt.yaml file not found")
 	} else {
-		fmt.Println("✅ .jitt.yaml file exists")
+		fmt.Fprintln(stdio.Out, "✅ .jitt.yaml file exists")
 
 		// Check if there's a project configured
 		cfg, err := config.Load()
@@ -32,33 +32,32 @@ func HandleDoctor(args []string) {
 		case cfg.Jira.Project == "":
 			warnings = append(warnings, "⚠️  No project configured in .jitt.yaml")
 		default:
-			fmt.Printf("✅ Project configured: %s\n", cfg.Jira.Project)
+			fmt.Fprintf(stdio.Out, "✅ Project configured: %s\n", cfg.Jira.Project)
 		}
 	}
 
 	// Print warnings
 	for _, warning := range warnings {
-		fmt.Println(warning)
+		fmt.Fprintln(stdio.Out, warning)
 	}
 
-	// Print issues and exit with error code if any found
+	// Print issues and fail if any found
 	if len(issues) > 0 {
-		fmt.Println()
+		fmt.Fprintln(stdio.Out)
 		for _, issue := range issues {
-			fmt.Println(issue)
+			fmt.Fprintln(stdio.Out, issue)
 		}
-		fmt.Println()
-		fmt.Println("Run 'jitt init' to set up your project.")
-		osExit(1)
-		return
+		fmt.Fprintln(stdio.Out)
+		fmt.Fprintln(stdio.Out, "Run 'jitt init' to set up your project.")
+		return &Error{Code: ExitFailure}
 	}
 
 	// All good!
+	fmt.Fprintln(stdio.Out)
 	if len(warnings) == 0 {
-		fmt.Println()
-		fmt.Println("🎉 Everything looks good!")
+		fmt.Fprintln(stdio.Out, "🎉 Everything looks good!")
 	} else {
-		fmt.Println()
-		fmt.Println("✨ Setup is functional but could be improved.")
+		fmt.Fprintln(stdio.Out, "✨ Setup is functional but could be improved.")
 	}
+	return nil
 }
@@ -8,6 +8,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
+
+	"github.com/bbommarito/jitt/internal/git/gittest"
 )
 
 // Helper function to reduce test duplication
@@ -60,7 +62,7 @@ var _ = Describe("jitt doctor command", func() {
 
 	Context("inside a Git repository", func() {
 		BeforeEach(func() {
-			Expect(os.Mkdir(filepath.Join(tmpDir, ".git"), 0o755)).To(Succeed())
+			initGitRepo()
 		})
 
 		Context("with no .jitt.yaml file", func() {
@@ -132,6 +134,48 @@ var _ = Describe("jitt doctor command", func() {
 		})
 	})
 
+	Context("in other repository layouts", func() {
+		doctorIn := func(dir string, env ...string) string {
+			command := exec.Command(pathToJittBinary, "doctor")
+			command.Dir = dir
+			command.Env = append(os.Environ(), env...)
+			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(session).Should(gexec.Exit())
+			return string(session.Out.Contents())
+		}
+
+		var main *gittest.Repo
+
+		BeforeEach(func() {
+			main = initGitRepo()
+			main.Commit("Initial commit")
+		})
+
+		It("should report a linked worktree", func() {
+			worktree := main.Worktree("feature")
+			Expect(doctorIn(worktree.Dir)).To(ContainSubstring("✅ Git repository found: linked worktree at"))
+		})
+
+		It("should report a submodule", func() {
+			lib := gittest.New(GinkgoT())
+			lib.Commit("Library")
+			sub := main.Submodule(lib, "vendor/lib")
+			Expect(doctorIn(sub.Dir)).To(ContainSubstring("✅ Git repository found: submodule at"))
+		})
+
+		It("should report a bare repository", func() {
+			bare := main.CloneBare()
+			Expect(doctorIn(bare.Dir)).To(ContainSubstring("✅ Git repository found: bare repository at"))
+		})
+
+		It("should honor GIT_DIR and GIT_WORK_TREE", func() {
+			elsewhere := GinkgoT().TempDir()
+			output := doctorIn(elsewhere, "GIT_DIR="+filepath.Join(tmpDir, ".git"), "GIT_WORK_TREE="+elsewhere)
+			Expect(output).To(ContainSubstring("✅ Git repository found: work tree at"))
+		})
+	})
+
 	Context("help message", func() {
 		It("should include doctor command in help", func() {
 			command := exec.Command(pathToJittBinary, "help")
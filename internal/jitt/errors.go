@@ -0,0 +1,96 @@
+package jitt
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// Exit codes of the jitt command
+const (
+	// ExitFailure means the checks ran and failed, or the command could not do its job
+	ExitFailure = 1
+	// ExitUsage means the arguments or flags were wrong
+	ExitUsage = 2
+	// ExitConfig means there is no Git repository, or no usable .jitt.yaml
+	ExitConfig = 3
+	// ExitNetwork means a remote such as Jira or a git server could not be reached
+	ExitNetwork = 4
+)
+
+// Error is a handler failure and the exit code it calls for. A nil Err means
+// the handler has already reported the problem.
+type Error struct {
+	Code int
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit status %d", e.Code)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func usageError(format string, args ...any) error {
+	return &Error{Code: ExitUsage, Err: fmt.Errorf(format, args...)}
+}
+
+func configError(format string, args ...any) error {
+	return &Error{Code: ExitConfig, Err: fmt.Errorf(format, args...)}
+}
+
+func failure(format string, args ...any) error {
+	return &Error{Code: ExitFailure, Err: fmt.Errorf(format, args...)}
+}
+
+func networkError(format string, args ...any) error {
+	return &Error{Code: ExitNetwork, Err: fmt.Errorf(format, args...)}
+}
+
+// jiraError reports err, from talking to Jira, as a network error when Jira
+// could not be reached and as a failure when it answered
+func jiraError(err error, format string, args ...any) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return networkError(format, args...)
+	}
+	return failure(format, args...)
+}
+
+// flagError turns a parseFlags error into a handler error; the flag package
+// has already printed what went wrong, and -h/--help is not a failure
+func flagError(err error) error {
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return &Error{Code: ExitUsage}
+}
+
+// ExitCode returns the code jitt exits with after a handler returned err
+func ExitCode(err error) int {
+	var e *Error
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &e):
+		return e.Code
+	}
+	return ExitFailure
+}
+
+// ReportError prints the message of a handler error to w, unless the handler has
+// already reported it, and returns the code to exit with
+func ReportError(w io.Writer, err error) int {
+	var e *Error
+	if err != nil && !errors.Is(err, flag.ErrHelp) && (!errors.As(err, &e) || e.Err != nil) {
+		fmt.Fprintln(w, err)
+	}
+	return ExitCode(err)
+}
@@ -0,0 +1,111 @@
+package jitt
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("ExitCode",
+	func(err error, expected int) {
+		Expect(ExitCode(err)).To(Equal(expected))
+	},
+	Entry("success", nil, 0),
+	Entry("help", flag.ErrHelp, 0),
+	Entry("plain errors", errors.New("boom"), ExitFailure),
+	Entry("usage errors", usageError("Usage: jitt x"), ExitUsage),
+	Entry("config errors", configError("no .jitt.yaml"), ExitConfig),
+	Entry("network errors", networkError("timeout"), ExitNetwork),
+	Entry("flag errors", flagError(errors.New("flag provided but not defined: -x")), ExitUsage),
+	Entry("unreachable Jira", jiraError(fmt.Errorf("jira: %w", &url.Error{Op: "Get", URL: "https://jira", Err: errors.New("refused")}), "Error"),
+		ExitNetwork),
+	Entry("Jira answering with an error", jiraError(errors.New("jira: 404"), "Error"), ExitFailure),
+)
+
+var _ = Describe("ReportError", func() {
+	It("should print the message unless the handler already reported it", func() {
+		var stderr bytes.Buffer
+
+		Expect(ReportError(&stderr, configError("Error loading config: %v", "bad"))).To(Equal(ExitConfig))
+		Expect(ReportError(&stderr, &Error{Code: ExitFailure})).To(Equal(ExitFailure))
+		Expect(ReportError(&stderr, nil)).To(Equal(0))
+		Expect(stderr.String()).To(Equal("Error loading config: bad\n"))
+	})
+})
+
+var _ = Describe("handlers in-process", func() {
+	var (
+		oldCwd string
+		stdio  IO
+		stdout *bytes.Buffer
+		stderr *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+
+		stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+		stdio = IO{In: strings.NewReader(""), Out: stdout, Err: stderr}
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should return doctor's findings as a failure without printing them twice", func() {
+		err := HandleDoctor(stdio, nil)
+
+		Expect(ExitCode(err)).To(Equal(ExitFailure))
+		Expect(stdout.String()).To(ContainSubstring("❌ Not inside a Git repository"))
+		Expect(stderr.String()).To(BeEmpty())
+	})
+
+	It("should return config errors for the caller to report", func() {
+		err := HandleConfig(stdio, []string{"project"})
+
+		Expect(ExitCode(err)).To(Equal(ExitConfig))
+		Expect(err).To(MatchError("Not inside a Git repo."))
+	})
+
+	It("should return usage errors for unknown keys", func() {
+		initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"), 0o600)).To(Succeed())
+
+		err := HandleConfig(stdio, []string{"colour"})
+
+		Expect(ExitCode(err)).To(Equal(ExitUsage))
+		Expect(err.Error()).To(HavePrefix("Unknown config key: colour"))
+		Expect(stdout.String()).To(BeEmpty())
+	})
+
+	It("should validate a message from the injected stdin", func() {
+		initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"), 0o600)).To(Succeed())
+
+		stdio.In = strings.NewReader("ABC-1 Fix the login form\n")
+		Expect(HandleValidate(stdio, []string{"-"})).To(Succeed())
+
+		stdio.In = strings.NewReader("Fix the login form\n")
+		err := HandleValidate(stdio, []string{"-"})
+		Expect(ExitCode(err)).To(Equal(ExitFailure))
+		Expect(stderr.String()).To(ContainSubstring("❌"))
+	})
+
+	It("should give every handler the same exit codes for usage and config errors", func() {
+		Expect(ExitCode(HandleStatus(stdio, []string{"--output", "xml"}))).To(Equal(ExitUsage))
+		Expect(ExitCode(HandleHook(stdio, []string{"post-rewrite"}))).To(Equal(ExitUsage))
+		Expect(ExitCode(HandleLog(stdio, []string{"--no-such-flag"}))).To(Equal(ExitUsage))
+		Expect(ExitCode(HandleLog(stdio, nil))).To(Equal(ExitConfig))
+		Expect(ExitCode(HandleChangelog(stdio, nil))).To(Equal(ExitConfig))
+	})
+})
@@ -0,0 +1,190 @@
+package jitt
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/bbommarito/jitt/internal/jira"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// fakeJira is an in-memory stand-in for the Jira REST API used by the CLI specs
+type fakeJira struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	issues       map[string]*fakeIssue
+	transitioned []string
+	comments     []string
+	worklogs     []map[string]any
+}
+
+type fakeIssue struct {
+	Summary     string
+	Description string
+	Status      string
+	Assignee    string
+	Custom      map[string]string
+}
+
+// fakeWorkflow lists the transitions offered on every issue, minus the one
+// leading to the issue's current status
+var fakeWorkflow = []jira.Transition{
+	{ID: "11", Name: "Start Progress", To: jira.Status{Name: "In Progress"}},
+	{ID: "21", Name: "Submit for Review", To: jira.Status{Name: "In Review"}},
+	{ID: "31", Name: "Resolve", To: jira.Status{Name: "Done"}},
+}
+
+func newFakeJira() *fakeJira {
+	f := &fakeJira{issues: make(map[string]*fakeIssue)}
+	f.server = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+func (f *fakeJira) Close() {
+	f.server.Close()
+}
+
+// addIssue registers an issue in the given status
+func (f *fakeJira) addIssue(key, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues[key] = &fakeIssue{Summary: "Summary of " + key, Status: status}
+}
+
+// assign sets the display name of an issue's assignee
+func (f *fakeJira) assign(key, name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues[key].Assignee = name
+}
+
+// describe sets an issue's description and custom text fields
+func (f *fakeJira) describe(key, description string, custom map[string]string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.issues[key].Description = description
+	f.issues[key].Custom = custom
+}
+
+func (f *fakeJira) status(key string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.issues[key].Status
+}
+
+// Transitioned returns the "KEY -> Status" transitions applied so far
+func (f *fakeJira) Transitioned() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.transitioned...)
+}
+
+// Comments returns the "KEY: body" comments posted so far
+func (f *fakeJira) Comments() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.comments...)
+}
+
+// Worklogs returns the worklog request bodies posted so far, with the issue key under "key"
+func (f *fakeJira) Worklogs() []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]map[string]any(nil), f.worklogs...)
+}
+
+// writeConfig writes a .jitt.yaml pointing at the fake server with extra YAML appended
+func (f *fakeJira) writeConfig(extra string) error {
+	content := "jira:\n  project: ABC\n  url: " + f.server.URL + "\n" + extra
+	return os.WriteFile(".jitt.yaml", []byte(content), 0o600)
+}
+
+func (f *fakeJira) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// A project exists when one of its issues does
+	if project, ok := strings.CutPrefix(r.URL.Path, "/rest/api/2/project/"); ok {
+		for key := range f.issues {
+			if lib.KeyProject(key) == project {
+				_ = json.NewEncoder(w).Encode(map[string]string{"key": project})
+				return
+			}
+		}
+		http.Error(w, `{"errorMessages":["No project could be found"]}`, http.StatusNotFound)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/")
+	key, action, _ := strings.Cut(path, "/")
+	issue, ok := f.issues[key]
+	if !ok {
+		http.Error(w, `{"errorMessages":["Issue does not exist"]}`, http.StatusNotFound)
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		fields := map[string]any{
+			"summary":     issue.Summary,
+			"description": issue.Description,
+			"status":      map[string]string{"name": issue.Status},
+		}
+		for name, value := range issue.Custom {
+			fields[name] = value
+		}
+		if issue.Assignee != "" {
+			fields["assignee"] = map[string]string{"displayName": issue.Assignee}
+		}
+		writeJSON(w, map[string]any{"key": key, "fields": fields})
+	case action == "transitions" && r.Method == http.MethodGet:
+		var available []jira.Transition
+		for _, t := range fakeWorkflow {
+			if t.To.Name != issue.Status {
+				available = append(available, t)
+			}
+		}
+		writeJSON(w, map[string]any{"transitions": available})
+	case action == "transitions" && r.Method == http.MethodPost:
+		var body struct {
+			Transition struct {
+				ID string `json:"id"`
+			} `json:"transition"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		for _, t := range fakeWorkflow {
+			if t.ID == body.Transition.ID {
+				issue.Status = t.To.Name
+				f.transitioned = append(f.transitioned, key+" -> "+t.To.Name)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		http.Error(w, `{"errorMessages":["Unknown transition"]}`, http.StatusBadRequest)
+	case action == "comment" && r.Method == http.MethodPost:
+		var body struct {
+			Body string `json:"body"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.comments = append(f.comments, key+": "+body.Body)
+		w.WriteHeader(http.StatusCreated)
+	case action == "worklog" && r.Method == http.MethodPost:
+		body := map[string]any{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		body["key"] = key
+		f.worklogs = append(f.worklogs, body)
+		w.WriteHeader(http.StatusCreated)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
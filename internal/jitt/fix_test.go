@@ -0,0 +1,81 @@
+package jitt
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("jitt validate --fix", func() {
+	var oldCwd string
+
+	BeforeEach(func() {
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+
+		initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\ncommit:\n  format: \"[{key}] {message}\"\n"), 0o600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should reject subjects that don't follow commit.format", func() {
+		session := runValidateMessage("fix bug ABC-123\n")
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring(
+			`1:1: subject does not follow commit.format "[{key}] {message}" (e.g. [ABC-123] Fix the login form)`))
+	})
+
+	It("should rewrite the file, keeping git's comments, and report the changes", func() {
+		Expect(os.WriteFile("COMMIT_EDITMSG", []byte("fix bug abc-123 ABC-123\n\nDetails\n# Please enter the commit message\n"), 0o600)).To(Succeed())
+
+		command := exec.Command(pathToJittBinary, "validate", "--fix", "COMMIT_EDITMSG")
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(0))
+		output := string(session.Out.Contents())
+		Expect(output).To(ContainSubstring("Fixed COMMIT_EDITMSG:"))
+		Expect(output).To(ContainSubstring("abc-123 → ABC-123"))
+		Expect(output).To(ContainSubstring("removed repeated keys from the subject"))
+		Expect(output).To(ContainSubstring(`subject: "fix bug ABC-123 ABC-123" → "[ABC-123] fix bug"`))
+
+		content, err := os.ReadFile("COMMIT_EDITMSG")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(content)).To(Equal("[ABC-123] fix bug\n\nDetails\n\n# Please enter the commit message\n"))
+	})
+
+	It("should use the ticket from the branch when the message has none", func() {
+		git("checkout", "-q", "-b", "feature/ABC-42-login")
+
+		session := runFixFromStdin("tweak login\n")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(strings.TrimSpace(string(session.Out.Contents()))).To(Equal("[ABC-42] tweak login"))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("added ABC-42 from branch feature/ABC-42-login"))
+	})
+
+	It("should fail when no ticket can be found", func() {
+		session := runFixFromStdin("tweak login\n")
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("no ticket key found in the message or the branch name"))
+	})
+})
+
+// runFixFromStdin runs 'jitt validate --fix -' with message on stdin
+func runFixFromStdin(message string) *gexec.Session {
+	command := exec.Command(pathToJittBinary, "validate", "--fix", "-")
+	command.Stdin = strings.NewReader(message)
+	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+	return session
+}
@@ -0,0 +1,74 @@
+package jitt
+
+import (
+	"context"
+	"fmt"
+
+	gitrepo "github.com/bbommarito/jitt/internal/git"
+)
+
+// repo runs git in the working directory of the command being handled
+var repo = gitrepo.New("")
+
+// runGit runs git with the given arguments and returns its trimmed stdout
+func runGit(args ...string) (string, error) {
+	return repo.Run(context.Background(), args...)
+}
+
+// runGitInput runs git like runGit, with extra environment variables and stdin
+func runGitInput(env []string, stdin string, args ...string) (string, error) {
+	r := &gitrepo.Repo{Dir: repo.Dir, Env: append(append([]string(nil), repo.Env...), env...)}
+	return r.RunInput(context.Background(), stdin, args...)
+}
+
+// locateRepo finds the repository git sees from the working directory
+func locateRepo() (gitrepo.Location, error) {
+	return repo.Locate(context.Background())
+}
+
+// describeRepo says what kind of repository loc is, and where
+func describeRepo(loc gitrepo.Location) string {
+	switch loc.Kind {
+	case gitrepo.KindLinkedWorktree:
+		return fmt.Sprintf("linked worktree at %s, sharing %s", loc.WorkTree, loc.CommonDir)
+	case gitrepo.KindSubmodule:
+		return fmt.Sprintf("submodule at %s, inside %s", loc.WorkTree, loc.Superproject)
+	case gitrepo.KindBare, gitrepo.KindGitDir:
+		return fmt.Sprintf("%s repository at %s", loc.Kind, loc.GitDir)
+	}
+	return fmt.Sprintf("%s at %s", loc.Kind, loc.WorkTree)
+}
+
+// currentBranch returns the short name of the checked out branch, or an error on a detached HEAD
+func currentBranch() (string, error) {
+	return repo.CurrentBranch(context.Background())
+}
+
+// hooksDir returns the directory git reads hooks from, honoring core.hooksPath
+func hooksDir() (string, error) {
+	return repo.HooksPath(context.Background())
+}
+
+// stagedFiles returns the paths staged for the next commit, relative to the repository root
+func stagedFiles() ([]string, error) {
+	return repo.StagedFiles(context.Background())
+}
+
+// commitMessages returns the full messages of the commits selected by the given revisions
+func commitMessages(revs ...string) ([]string, error) {
+	commits, err := commitsInRange(revs...)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]string, 0, len(commits))
+	for _, c := range commits {
+		messages = append(messages, c.Message)
+	}
+	return messages, nil
+}
+
+// commitsInRange returns the commits selected by the given revisions, newest first
+func commitsInRange(revs ...string) ([]gitrepo.Commit, error) {
+	return repo.Log(context.Background(), revs...)
+}
@@ -0,0 +1,325 @@
+package jitt
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitrepo "github.com/bbommarito/jitt/internal/git"
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// hookMarker identifies hook scripts written by 'jitt hook install'
+const hookMarker = "# installed by jitt"
+
+// managedHooks are the git hooks 'jitt hook install' sets up
+var managedHooks = []string{"prepare-commit-msg", "commit-msg", "post-checkout", "pre-push", "post-merge", "reference-transaction"}
+
+// HandleHook handles the 'jitt hook' command. Git runs it from the scripts
+// installed by 'jitt hook install', passing along the hook's own arguments.
+func HandleHook(stdio IO, args []string) error {
+	if len(args) == 0 {
+		return usageError("Usage: jitt hook <install|prepare-commit-msg|commit-msg|post-checkout|pre-push|post-merge|reference-transaction> [arguments]")
+	}
+
+	if args[0] == "install" {
+		return installHooks(stdio, args[1:])
+	}
+	if !containsString(managedHooks, args[0]) {
+		return usageError("Unknown hook: %s\nAvailable hooks: %s", args[0], strings.Join(managedHooks, ", "))
+	}
+	if args[0] == "commit-msg" && len(args) < 2 {
+		return usageError("Usage: jitt hook commit-msg <commit-message-file>")
+	}
+
+	// Git runs reference-transaction for every ref update; only committed
+	// updates of remote-tracking refs, as after a push, concern jitt
+	if args[0] == "reference-transaction" && !updatesRemoteRefs(args[1:], stdio.In) {
+		return nil
+	}
+
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "prepare-commit-msg":
+		hookPrepareCommitMsg(stdio.Err, cfg, args[1:])
+	case "commit-msg":
+		return validateMessageFile(stdio, cfg, workingTreeScopes(), args[1], false)
+	case "post-checkout":
+		hookPostCheckout(stdio, cfg, args[1:])
+	case "pre-push":
+		hookPrePush(stdio, cfg, args[1:])
+	case "post-merge":
+		hookPostMerge(stdio, cfg)
+	case "reference-transaction":
+		runConfirmedSmartCommits(stdio, cfg)
+	}
+	return nil
+}
+
+func installHooks(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("hook install", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	force := fs.Bool("force", false, "overwrite existing hooks that were not installed by jitt")
+	if _, err := parseFlags(fs, args); err != nil {
+		return flagError(err)
+	}
+
+	if !isGitRepo() {
+		return configError("Not inside a Git repo. Hooks not installed")
+	}
+
+	dir, err := hooksDir()
+	if err != nil {
+		return failure("Error locating hooks directory: %v", err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return failure("Error creating hooks directory: %v", err)
+	}
+
+	failed := false
+	for _, name := range managedHooks {
+		path := filepath.Join(dir, name)
+		if existing, err := os.ReadFile(path); err == nil && !strings.Contains(string(existing), hookMarker) && !*force {
+			fmt.Fprintf(stdio.Err, "%s hook already exists and was not installed by jitt — use --force to overwrite\n", name)
+			failed = true
+			continue
+		}
+
+		script := fmt.Sprintf("#!/bin/sh\n%s\nexec jitt hook %s \"$@\"\n", hookMarker, name)
+		//nolint:gosec // hooks must be executable
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+			fmt.Fprintf(stdio.Err, "Error writing %s hook: %v\n", name, err)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(stdio.Out, "Installed %s hook\n", name)
+	}
+
+	if failed {
+		return &Error{Code: ExitFailure}
+	}
+	return nil
+}
+
+// hookPrepareCommitMsg adds a trailer referencing the branch's ticket to new
+// commit messages in trailer mode (git passes <message file> [<source> [<sha>]]).
+// Merges and squashes keep the message git prepared.
+func hookPrepareCommitMsg(w io.Writer, cfg *config.Config, args []string) {
+	if !lib.TrailerMode(cfg) || len(args) == 0 {
+		return
+	}
+	if len(args) > 1 && (args[1] == "merge" || args[1] == "squash") {
+		return
+	}
+
+	branch, err := currentBranch()
+	if err != nil {
+		return
+	}
+	key := branchTicket(cfg, branch)
+	if key == "" {
+		return
+	}
+
+	raw, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintf(w, "jitt: could not read commit message: %v\n", err)
+		return
+	}
+	if containsString(lib.ReferencedKeys(cfg, lib.StripComments(string(raw))), key) {
+		return
+	}
+
+	trailer := fmt.Sprintf("%s: %s", cfg.Commit.Trailer, key)
+	if _, err := runGit("interpret-trailers", "--in-place", "--if-exists", "addIfDifferent", "--trailer", trailer, args[0]); err != nil {
+		fmt.Fprintf(w, "jitt: could not add %s trailer: %v\n", cfg.Commit.Trailer, err)
+	}
+}
+
+// hookPostCheckout fires the branch_started event when a branch has just
+// been created (git passes <previous HEAD> <new HEAD> <branch checkout flag>)
+func hookPostCheckout(stdio IO, cfg *config.Config, args []string) {
+	if len(args) < 3 || args[2] != "1" || args[0] != args[1] {
+		return
+	}
+
+	branch, err := currentBranch()
+	if err != nil || !isNewBranch(branch) {
+		return
+	}
+
+	runWorkflow(stdio, cfg, "branch started", cfg.Workflow.BranchStarted, lib.BranchKeys(cfg, branch))
+}
+
+// isNewBranch reports whether the branch has just been created: its reflog
+// holds only the creation entry and HEAD has moved onto it exactly once
+func isNewBranch(branch string) bool {
+	out, err := runGit("reflog", "show", "--format=%gs", "refs/heads/"+branch, "--")
+	if err != nil {
+		return false
+	}
+	entries := strings.Split(out, "\n")
+	if len(entries) != 1 || !strings.HasPrefix(entries[0], "branch: Created from") {
+		return false
+	}
+
+	out, err = runGit("reflog", "show", "--format=%gs", "HEAD", "--")
+	if err != nil {
+		return false
+	}
+	checkouts := 0
+	for _, entry := range strings.Split(out, "\n") {
+		if strings.HasPrefix(entry, "checkout: moving from ") && strings.HasSuffix(entry, " to "+branch) {
+			checkouts++
+		}
+	}
+	return checkouts == 1
+}
+
+// hookPrePush fires first_push for branches the remote does not have yet and
+// tag_created for new tags, and queues the pushed commits so their smart
+// commits run once the remote has them. Git passes the remote name and URL
+// as arguments and feeds one line per ref being pushed:
+// <local ref> <local sha> <remote ref> <remote sha>
+func hookPrePush(stdio IO, cfg *config.Config, args []string) {
+	remote := ""
+	if len(args) > 0 {
+		remote = args[0]
+	}
+	// Catch up on earlier pushes whose remote-tracking refs were updated
+	// without the reference-transaction hook
+	runConfirmedSmartCommits(stdio, cfg)
+
+	var pushed []gitrepo.Commit
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(stdio.In)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		localRef, localSHA, remoteSHA := fields[0], fields[1], fields[3]
+		if gitrepo.IsZeroSHA(localSHA) {
+			continue
+		}
+
+		if strings.HasPrefix(localRef, "refs/heads/") {
+			for _, c := range pushedCommits(remote, localSHA, remoteSHA) {
+				if !seen[c.SHA] {
+					seen[c.SHA] = true
+					pushed = append(pushed, c)
+				}
+			}
+		}
+		if !gitrepo.IsZeroSHA(remoteSHA) {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(localRef, "refs/heads/"):
+			branch := strings.TrimPrefix(localRef, "refs/heads/")
+			runWorkflow(stdio, cfg, "first push", cfg.Workflow.FirstPush, lib.BranchKeys(cfg, branch))
+		case strings.HasPrefix(localRef, "refs/tags/"):
+			runWorkflow(stdio, cfg, "tag created", cfg.Workflow.TagCreated, taggedTicketKeys(cfg, localSHA))
+		}
+	}
+
+	queueSmartCommits(stdio, cfg, remote, pushed)
+}
+
+// updatesRemoteRefs reports whether a reference-transaction hook call
+// (git passes the state and feeds "<old> <new> <ref>" lines) committed an
+// update of a remote-tracking ref
+func updatesRemoteRefs(args []string, stdin io.Reader) bool {
+	if len(args) == 0 || args[0] != "committed" {
+		return false
+	}
+	updates, err := readRefUpdates(stdin)
+	if err != nil {
+		return false
+	}
+	for _, u := range updates {
+		if strings.HasPrefix(u.Ref, "refs/remotes/") && !gitrepo.IsZeroSHA(u.New) {
+			return true
+		}
+	}
+	return false
+}
+
+// pushedCommits returns the commits a push of localSHA adds to the remote,
+// oldest first: everything since remoteSHA, or for a new branch everything
+// the remote doesn't already have
+func pushedCommits(remote, localSHA, remoteSHA string) []gitrepo.Commit {
+	revs := []string{"--reverse", localSHA}
+	switch {
+	case !gitrepo.IsZeroSHA(remoteSHA):
+		revs = append(revs, "^"+remoteSHA)
+	case remote != "":
+		revs = append(revs, "--not", "--remotes="+remote)
+	}
+
+	commits, err := commitsInRange(revs...)
+	if err != nil {
+		return nil
+	}
+	return commits
+}
+
+// taggedTicketKeys returns the tickets referenced by commits since the previous tag
+func taggedTicketKeys(cfg *config.Config, tagSHA string) []string {
+	revs := []string{tagSHA}
+	if previous, err := runGit("describe", "--tags", "--abbrev=0", tagSHA+"^{commit}^"); err == nil {
+		revs = append(revs, "^"+previous)
+	}
+
+	messages, err := commitMessages(revs...)
+	if err != nil {
+		return nil
+	}
+	return lib.TicketKeys(cfg, strings.Join(messages, "\n"))
+}
+
+// hookPostMerge fires the merged event for tickets in commits merged into the main branch
+func hookPostMerge(stdio IO, cfg *config.Config) {
+	branch, err := currentBranch()
+	if err != nil || branch != cfg.Workflow.MainBranch {
+		return
+	}
+
+	messages, err := commitMessages("ORIG_HEAD..HEAD")
+	if err != nil {
+		return
+	}
+	runWorkflow(stdio, cfg, "merge", cfg.Workflow.Merged, lib.TicketKeys(cfg, strings.Join(messages, "\n")))
+}
+
+// runWorkflow applies the configured transition to each ticket. Failures are
+// reported as warnings: a Jira hiccup should never block a push or merge.
+func runWorkflow(stdio IO, cfg *config.Config, event, target string, keys []string) {
+	if target == "" || len(keys) == 0 {
+		return
+	}
+
+	client, err := newJiraClient(cfg)
+	if err != nil {
+		fmt.Fprintf(stdio.Err, "jitt: skipping %s transition: %v\n", event, err)
+		return
+	}
+
+	for _, key := range keys {
+		if err := transitionIssue(context.Background(), stdio.Out, client, key, target, cfg.Workflow.DryRun); err != nil {
+			fmt.Fprintf(stdio.Err, "jitt: %s transition failed: %v\n", event, err)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package jitt
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+func runHookCommand(stdin string, args ...string) *gexec.Session {
+	command := exec.Command(pathToJittBinary, append([]string{"hook"}, args...)...)
+	command.Stdin = strings.NewReader(stdin)
+	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+	return session
+}
+
+var _ = Describe("jitt hook command", func() {
+	var (
+		tmpDir string
+		oldCwd string
+		fake   *fakeJira
+	)
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		fake = newFakeJira()
+		fake.addIssue("ABC-1", "To Do")
+		fake.addIssue("ABC-2", "In Review")
+	})
+
+	AfterEach(func() {
+		fake.Close()
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should show usage without a hook name", func() {
+		session := runHookCommand("")
+
+		Eventually(session).Should(gexec.Exit(ExitUsage))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("Usage: jitt hook"))
+	})
+
+	Describe("install", func() {
+		It("should refuse outside a Git repository", func() {
+			session := runHookCommand("", "install")
+
+			Eventually(session).Should(gexec.Exit(ExitConfig))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("Not inside a Git repo"))
+		})
+
+		Context("inside a Git repository", func() {
+			BeforeEach(func() {
+				initGitRepo()
+			})
+
+			It("should write executable hook scripts that call jitt", func() {
+				session := runHookCommand("", "install")
+
+				Eventually(session).Should(gexec.Exit(0))
+				Expect(string(session.Out.Contents())).To(ContainSubstring("Installed pre-push hook"))
+
+				info, err := os.Stat(filepath.Join(".git", "hooks", "pre-push"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Mode().Perm() & 0o100).NotTo(BeZero())
+
+				content, err := os.ReadFile(filepath.Join(".git", "hooks", "post-merge"))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).To(ContainSubstring(`exec jitt hook post-merge "$@"`))
+			})
+
+			It("should not overwrite foreign hooks without --force", func() {
+				foreign := filepath.Join(".git", "hooks", "pre-push")
+				Expect(os.WriteFile(foreign, []byte("#!/bin/sh\necho mine\n"), 0o600)).To(Succeed())
+
+				session := runHookCommand("", "install")
+				Eventually(session).Should(gexec.Exit(1))
+				Expect(string(session.Err.Contents())).To(ContainSubstring("pre-push hook already exists"))
+				Expect(os.ReadFile(foreign)).To(ContainSubstring("echo mine"))
+
+				session = runHookCommand("", "install", "--force")
+				Eventually(session).Should(gexec.Exit(0))
+				Expect(os.ReadFile(foreign)).To(ContainSubstring("exec jitt hook pre-push"))
+			})
+		})
+	})
+
+	Context("with workflow rules configured", func() {
+		BeforeEach(func() {
+			initGitRepo()
+			Expect(fake.writeConfig("workflow:\n" +
+				"  branch_started: In Progress\n" +
+				"  first_push: In Review\n" +
+				"  merged: Done\n" +
+				"  tag_created: Done\n")).To(Succeed())
+			commit("Initial commit")
+		})
+
+		It("should transition the branch ticket when a branch is started", func() {
+			head := git("rev-parse", "HEAD")
+			git("checkout", "-q", "-b", "feature/ABC-1-login")
+
+			session := runHookCommand("", "post-checkout", head, head, "1")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(fake.Transitioned()).To(ConsistOf("ABC-1 -> In Progress"))
+		})
+
+		It("should ignore checkouts of existing branches", func() {
+			head := git("rev-parse", "HEAD")
+			git("branch", "ABC-1-existing")
+			git("checkout", "-q", "ABC-1-existing")
+			git("checkout", "-q", "main")
+			git("checkout", "-q", "ABC-1-existing")
+
+			session := runHookCommand("", "post-checkout", head, head, "1")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(fake.Transitioned()).To(BeEmpty())
+		})
+
+		It("should transition on the first push of a branch only", func() {
+			sha := git("rev-parse", "HEAD")
+			zero := strings.Repeat("0", 40)
+
+			session := runHookCommand("refs/heads/ABC-1-login "+sha+" refs/heads/ABC-1-login "+sha+"\n",
+				"pre-push", "origin", "url")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(fake.Transitioned()).To(BeEmpty())
+
+			session = runHookCommand("refs/heads/ABC-1-login "+sha+" refs/heads/ABC-1-login "+zero+"\n",
+				"pre-push", "origin", "url")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(fake.Transitioned()).To(ConsistOf("ABC-1 -> In Review"))
+		})
+
+		It("should be idempotent for tickets already in the target status", func() {
+			sha := git("rev-parse", "HEAD")
+			zero := strings.Repeat("0", 40)
+
+			session := runHookCommand("refs/heads/ABC-2-api "+sha+" refs/heads/ABC-2-api "+zero+"\n",
+				"pre-push", "origin", "url")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("ABC-2 is already in In Review"))
+			Expect(fake.Transitioned()).To(BeEmpty())
+		})
+
+		It("should transition tickets referenced since the previous tag when a tag is pushed", func() {
+			git("tag", "v1.0.0")
+			commit("ABC-1 add login")
+			commit("ABC-2 add api")
+			git("tag", "v1.1.0")
+			sha := git("rev-parse", "v1.1.0")
+			zero := strings.Repeat("0", 40)
+
+			session := runHookCommand("refs/tags/v1.1.0 "+sha+" refs/tags/v1.1.0 "+zero+"\n",
+				"pre-push", "origin", "url")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(fake.Transitioned()).To(ConsistOf("ABC-1 -> Done", "ABC-2 -> Done"))
+		})
+
+		It("should transition tickets merged into the main branch", func() {
+			git("checkout", "-q", "-b", "feature")
+			commit("ABC-1 add login")
+			git("checkout", "-q", "main")
+			git("merge", "-q", "--ff-only", "feature")
+
+			session := runHookCommand("", "post-merge", "0")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(fake.Transitioned()).To(ConsistOf("ABC-1 -> Done"))
+		})
+
+		It("should ignore merges into other branches", func() {
+			git("checkout", "-q", "-b", "feature")
+			git("checkout", "-q", "-b", "other")
+			commit("ABC-1 add login")
+			git("checkout", "-q", "feature")
+			git("merge", "-q", "--ff-only", "other")
+
+			session := runHookCommand("", "post-merge", "0")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(fake.Transitioned()).To(BeEmpty())
+		})
+
+		It("should warn without failing when Jira rejects the transition", func() {
+			sha := git("rev-parse", "HEAD")
+			zero := strings.Repeat("0", 40)
+
+			session := runHookCommand("refs/heads/ABC-404-gone "+sha+" refs/heads/ABC-404-gone "+zero+"\n",
+				"pre-push", "origin", "url")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("first push transition failed"))
+		})
+	})
+})
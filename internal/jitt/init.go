@@ -1,64 +1,161 @@
 package jitt
 
 import (
+	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 
-	"github.com/bbommarito/jitt/internal/config"
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
 )
 
-var osExit = os.Exit
-
 func HasConfigFile() bool {
 	return config.Exists()
 }
 
+// isGitRepo reports whether git finds a repository here, the way git itself
+// does: honoring GIT_DIR and GIT_WORK_TREE, worktree and submodule .git files,
+// and bare repositories
 func isGitRepo() bool {
-	dir, err := os.Getwd()
+	_, err := locateRepo()
+	return err == nil
+}
+
+// HandleInit handles the 'jitt init' command
+func HandleInit(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	mapPaths := fs.Bool("projects", false, "interactively map repository paths to Jira projects")
+	interactive := fs.Bool("interactive", false, "ask for each setting, suggesting what the repository's history uses")
+	yes := fs.Bool("yes", false, "use the suggested answers without asking (implies --interactive)")
+	var opts config.InitOptions
+	fs.StringVar(&opts.Project, "project", "", "Jira project key (same as the positional argument)")
+	fs.StringVar(&opts.JiraURL, "jira-url", "", "Jira base URL, e.g. https://example.atlassian.net")
+	fs.StringVar(&opts.CommitPosition, "commit-position", "", "where commits reference tickets: subject or trailer")
+	fs.StringVar(&opts.CommitFormat, "commit-format", "", "commit subject layout, e.g. \"{key} {message}\"")
+	fs.StringVar(&opts.BranchPattern, "branch-pattern", "", "regular expression branch names must match")
+	hooks := fs.Bool("install-hooks", false, "install jitt's git hooks")
+	fs.StringVar(&opts.Extends.Source, "from", "", "extend a shared base config: a local file or a git repository URL")
+	fs.StringVar(&opts.Extends.Ref, "from-ref", "", "branch, tag or commit of the git repository to pin (default HEAD)")
+	fs.StringVar(&opts.Extends.Path, "from-path", "", "file within the git repository (default .jitt.yaml)")
+	positional, err := parseFlags(fs, args)
 	if err != nil {
-		return false
+		return flagError(err)
 	}
 
-	for {
-		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
-			return true
-		}
+	loc, err := locateRepo()
+	if err != nil {
+		return configError("Not inside a Git repo. Config not created")
+	}
+	if !loc.HasWorkTree() {
+		return configError("Not inside a working tree (%s repository). Config not created", loc.Kind)
+	}
 
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			return false
-		}
+	if HasConfigFile() {
+		return failure(".jitt.yaml already exists — not overwriting.")
+	}
 
-		dir = parent
+	if len(positional) >= 1 && opts.Project == "" {
+		opts.Project = positional[0]
+	}
+	if err := validateInitOptions(opts); err != nil {
+		return usageError("Error: %v", err)
 	}
-}
 
-// HandleInit handles the 'jitt init' command
-func HandleInit(args []string) {
-	if !isGitRepo() {
-		fmt.Fprintln(os.Stderr, "Not inside a Git repo. Config not created")
-		osExit(1)
-		return
+	if err := prepareBase(stdio.Out, opts.Extends); err != nil {
+		return err
 	}
 
-	if HasConfigFile() {
-		fmt.Fprintln(os.Stderr, ".jitt.yaml already exists — not overwriting.")
-		osExit(1)
-		return
+	in := stdio.reader()
+	if *interactive || *yes {
+		opts, *hooks = runInitWizard(in, stdio.Out, opts, *hooks, *yes)
+	}
+	if *mapPaths {
+		opts.Projects = promptProjectMappings(in, stdio.Out)
 	}
 
-	var project string
-	if len(args) >= 1 {
-		project = args[0]
+	err = config.CreateWithOptions(opts)
+	if err != nil {
+		return failure("Error creating .jitt.yaml: %v", err)
 	}
 
-	err := config.Create(project)
+	fmt.Fprintln(stdio.Out, ".jitt.yaml created")
+
+	if *hooks {
+		return installHooks(stdio, nil)
+	}
+	return nil
+}
+
+// prepareBase checks the base config 'jitt init --from' extends, vendoring
+// it first when it comes from a git repository
+func prepareBase(out io.Writer, ext config.ExtendsConfig) error {
+	switch {
+	case ext.Source == "":
+		return nil
+	case ext.IsGit():
+		sha, err := vendorBase(".", ext)
+		if err != nil {
+			return &Error{Code: ExitCode(err), Err: fmt.Errorf("Error: %w", err)}
+		}
+		fmt.Fprintf(out, "Vendored base config from %s at %s into %s\n", ext.Source, sha[:7],
+			filepath.ToSlash(filepath.Join(config.VendorDir, config.BaseFileName)))
+		return nil
+	case ext.Ref != "" || ext.Path != "":
+		return usageError("Error: --from-ref and --from-path only apply to git repositories")
+	}
+
+	data, err := os.ReadFile(ext.BaseFile("."))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating .jitt.yaml: %v\n", err)
-		osExit(1)
-		return
+		return configError("Error: reading base config: %v", err)
 	}
+	if err := config.CheckBase(data); err != nil {
+		return configError("Error: %s is not a valid jitt config: %v", ext.Source, err)
+	}
+	return nil
+}
 
-	fmt.Println(".jitt.yaml created")
+// validateInitOptions checks the answers given as flags to 'jitt init'
+func validateInitOptions(opts config.InitOptions) error {
+	switch opts.CommitPosition {
+	case "", config.CommitPositionSubject, config.CommitPositionTrailer:
+	default:
+		return fmt.Errorf("--commit-position must be %s or %s", config.CommitPositionSubject, config.CommitPositionTrailer)
+	}
+	if opts.CommitFormat != "" {
+		if _, err := lib.CommitFormatPattern(opts.CommitFormat); err != nil {
+			return err
+		}
+	}
+	if _, err := regexp.Compile(opts.BranchPattern); err != nil {
+		return fmt.Errorf("--branch-pattern: %w", err)
+	}
+	return nil
+}
+
+// promptProjectMappings asks for path globs and the project keys owning them
+// until an empty glob is entered
+func promptProjectMappings(in *bufio.Reader, out io.Writer) []config.ProjectMapping {
+	fmt.Fprintln(out, "Map repository paths to Jira projects (leave the path empty to finish).")
+
+	var projects []config.ProjectMapping
+	for {
+		globs, ok := prompt(in, out, "Path globs (e.g. services/billing/**): ")
+		if !ok || globs == "" {
+			return projects
+		}
+
+		keys, _ := prompt(in, out, fmt.Sprintf("Project keys for %s: ", globs))
+		mapping := config.ProjectMapping{Paths: splitList(globs), Keys: splitList(strings.ToUpper(keys))}
+		if len(mapping.Keys) == 0 {
+			fmt.Fprintln(out, "No project keys given — skipping.")
+			continue
+		}
+		projects = append(projects, mapping)
+	}
 }
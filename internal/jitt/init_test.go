@@ -4,7 +4,9 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 
+	lib "github.com/bbommarito/jitt/pkg/jitt"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
@@ -35,7 +37,7 @@ var _ = Describe("jitt init command", func() {
 			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(session).Should(gexec.Exit(1))
+			Eventually(session).Should(gexec.Exit(ExitConfig))
 			Expect(string(session.Err.Contents())).To(ContainSubstring("Not inside a Git repo"))
 			Expect(string(session.Err.Contents())).To(ContainSubstring("Config not created"))
 
@@ -48,7 +50,7 @@ var _ = Describe("jitt init command", func() {
 			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(session).Should(gexec.Exit(1))
+			Eventually(session).Should(gexec.Exit(ExitConfig))
 			Expect(string(session.Err.Contents())).To(ContainSubstring("Not inside a Git repo"))
 			Expect(".jitt.yaml").NotTo(BeAnExistingFile())
 		})
@@ -66,9 +68,23 @@ var _ = Describe("jitt init command", func() {
 		})
 	})
 
+	Context("in a bare repository", func() {
+		It("should refuse, as there is no working tree to hold .jitt.yaml", func() {
+			Expect(exec.Command("git", "init", "-q", "--bare").Run()).To(Succeed())
+
+			command := exec.Command(pathToJittBinary, "init", "MYPROJECT")
+			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(session).Should(gexec.Exit(ExitConfig))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("Not inside a working tree (bare repository)"))
+			Expect(".jitt.yaml").NotTo(BeAnExistingFile())
+		})
+	})
+
 	Context("inside a Git repository", func() {
 		BeforeEach(func() {
-			Expect(os.Mkdir(filepath.Join(tmpDir, ".git"), 0o755)).To(Succeed())
+			initGitRepo()
 		})
 
 		Context("with no existing config file", func() {
@@ -104,6 +120,134 @@ var _ = Describe("jitt init command", func() {
 			})
 		})
 
+		Context("with --projects", func() {
+			It("should scaffold path-to-project mappings from the answers", func() {
+				command := exec.Command(pathToJittBinary, "init", "ABC", "--projects")
+				command.Stdin = strings.NewReader("services/billing/**\nbill\nweb/**, *.css\nWEB UI\ndocs/**\n\n\n")
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session).Should(gexec.Exit(0))
+				output := string(session.Out.Contents())
+				Expect(output).To(ContainSubstring("Project keys for services/billing/**:"))
+				Expect(output).To(ContainSubstring("No project keys given — skipping."))
+				Expect(output).To(ContainSubstring(".jitt.yaml created"))
+
+				content, err := os.ReadFile(".jitt.yaml")
+				Expect(err).To(Succeed())
+				Expect(string(content)).To(ContainSubstring("project: ABC"))
+				Expect(string(content)).To(ContainSubstring("- services/billing/**"))
+				Expect(string(content)).To(ContainSubstring("- BILL"))
+				Expect(string(content)).To(ContainSubstring("- '*.css'"))
+				Expect(string(content)).To(ContainSubstring("- UI"))
+				Expect(string(content)).NotTo(ContainSubstring("docs/**"))
+			})
+
+			It("should stop prompting at end of input", func() {
+				command := exec.Command(pathToJittBinary, "init", "--projects")
+				command.Stdin = strings.NewReader("web/**\nWEB\n")
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(session).Should(gexec.Exit(0))
+				Expect(os.ReadFile(".jitt.yaml")).To(ContainSubstring("- WEB"))
+			})
+		})
+
+		Context("with --interactive", func() {
+			runInit := func(stdin string, args ...string) *gexec.Session {
+				command := exec.Command(pathToJittBinary, append([]string{"init"}, args...)...)
+				command.Stdin = strings.NewReader(stdin)
+				session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+				return session
+			}
+
+			BeforeEach(func() {
+				commit("[ABC-1] Add login")
+				commit("[ABC-2] Add logout")
+				commit("[DEF-3] Fix the build")
+				commit("Update README")
+				git("branch", "feature/ABC-4-profile")
+				git("branch", "bugfix/abc-5-crash")
+			})
+
+			It("should suggest what the repository uses and write the answers", func() {
+				session := runInit("\nhttps://jira.example.com\n\n\n\nn\n", "--interactive")
+
+				Eventually(session).Should(gexec.Exit(0))
+				output := string(session.Out.Contents())
+				Expect(output).To(ContainSubstring("Project keys seen in this repository: ABC (4), DEF (1)"))
+				Expect(output).To(ContainSubstring("Jira project key [ABC]:"))
+				Expect(output).To(ContainSubstring("Commit subject format ('-' to allow the key anywhere) [[{key}] {message}]:"))
+				Expect(output).To(ContainSubstring("[(?i)^(bugfix|feature)/[A-Z][A-Z0-9_]+-[0-9]+]:"))
+				Expect(output).To(ContainSubstring(".jitt.yaml created"))
+				Expect(output).NotTo(ContainSubstring("Installed"))
+
+				content, err := os.ReadFile(".jitt.yaml")
+				Expect(err).To(Succeed())
+				Expect(string(content)).To(ContainSubstring("project: ABC"))
+				Expect(string(content)).To(ContainSubstring("url: https://jira.example.com"))
+				Expect(string(content)).To(ContainSubstring("position: subject"))
+				Expect(string(content)).To(ContainSubstring("format: '[{key}] {message}'"))
+				Expect(string(content)).To(ContainSubstring("pattern: (?i)^(bugfix|feature)/[A-Z][A-Z0-9_]+-[0-9]+"))
+			})
+
+			It("should ask again after invalid answers and offer to install hooks", func() {
+				session := runInit("def\n-\nsideways\ntrailer\n[(\n^feature/\n\n", "--interactive")
+
+				Eventually(session).Should(gexec.Exit(0))
+				output := string(session.Out.Contents())
+				Expect(output).To(ContainSubstring("Please answer subject or trailer."))
+				Expect(output).NotTo(ContainSubstring("Commit subject format"))
+				Expect(output).To(ContainSubstring("Invalid regular expression"))
+				Expect(output).To(ContainSubstring("Installed commit-msg hook"))
+
+				content, err := os.ReadFile(".jitt.yaml")
+				Expect(err).To(Succeed())
+				Expect(string(content)).To(ContainSubstring("project: DEF"))
+				Expect(string(content)).NotTo(ContainSubstring("url:"))
+				Expect(string(content)).To(ContainSubstring("position: trailer"))
+				Expect(string(content)).To(ContainSubstring("pattern: ^feature/"))
+				Expect(filepath.Join(".git", "hooks", "commit-msg")).To(BeAnExistingFile())
+			})
+
+			It("should detect only the keys the base config recognises", func() {
+				Expect(os.WriteFile("base.yaml", []byte("keys:\n  deny: [DEF]\n"), 0o600)).To(Succeed())
+				git("branch", "feature/xyz-6-search")
+
+				session := runInit("", "--yes", "--from", "base.yaml")
+
+				Eventually(session).Should(gexec.Exit(0))
+				output := string(session.Out.Contents())
+				Expect(output).To(ContainSubstring("Project keys seen in this repository: ABC (4)\n"))
+				Expect(output).NotTo(ContainSubstring("XYZ"))
+			})
+
+			It("should take every answer from flags with --yes", func() {
+				session := runInit("", "--yes", "--project", "XYZ", "--jira-url", "https://x.example.com",
+					"--commit-format", "{key}: {message}", "--branch-pattern", "^XYZ-[0-9]+", "--install-hooks")
+
+				Eventually(session).Should(gexec.Exit(0))
+				Expect(string(session.Out.Contents())).To(ContainSubstring("Installed commit-msg hook"))
+
+				content, err := os.ReadFile(".jitt.yaml")
+				Expect(err).To(Succeed())
+				Expect(string(content)).To(ContainSubstring("project: XYZ"))
+				Expect(string(content)).To(ContainSubstring("url: https://x.example.com"))
+				Expect(string(content)).To(ContainSubstring("format: '{key}: {message}'"))
+				Expect(string(content)).To(ContainSubstring("pattern: ^XYZ-[0-9]+"))
+			})
+
+			It("should reject invalid answers given as flags", func() {
+				session := runInit("", "--commit-position", "footer")
+
+				Eventually(session).Should(gexec.Exit(ExitUsage))
+				Expect(string(session.Err.Contents())).To(ContainSubstring("--commit-position must be subject or trailer"))
+				Expect(".jitt.yaml").NotTo(BeAnExistingFile())
+			})
+		})
+
 		Context("with existing .jitt.yaml file", func() {
 			BeforeEach(func() {
 				Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: existing"), 0o600)).To(Succeed())
@@ -132,7 +276,7 @@ var _ = Describe("jitt init command", func() {
 			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(session).Should(gexec.Exit(1))
+			Eventually(session).Should(gexec.Exit(ExitUsage))
 			output := string(session.Out.Contents())
 			Expect(output).To(ContainSubstring("jitt - Jira + Git + Tiny Tooling"))
 			Expect(output).To(ContainSubstring("Usage: jitt <command>"))
@@ -145,9 +289,34 @@ var _ = Describe("jitt init command", func() {
 			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
 
-			Eventually(session).Should(gexec.Exit(1))
+			Eventually(session).Should(gexec.Exit(ExitUsage))
 			output := string(session.Err.Contents())
 			Expect(output).To(ContainSubstring("jitt: unknown command \"unknown-command\""))
 		})
 	})
 })
+
+var _ = DescribeTable("detecting a branch pattern",
+	func(branches []string, expected string) {
+		keys := lib.KeyParser{Projects: []string{"ABC"}, AnyProject: true, IgnoreCase: true, Deny: []string{"DEF"}}
+		Expect(repoHistory{Branches: branches}.detectBranchPattern(keys)).To(Equal(expected))
+	},
+	Entry("no ticket branches", []string{"main", "develop"}, ""),
+	Entry("bare keys", []string{"main", "ABC-1-login"}, "^[A-Z][A-Z0-9_]+-[0-9]+"),
+	Entry("prefixes", []string{"feature/ABC-1", "fix/ABC-2-x", "feature/ABC-3"}, "^(feature|fix)/[A-Z][A-Z0-9_]+-[0-9]+"),
+	Entry("optional prefixes", []string{"feature/ABC-1", "ABC-2"}, "^((feature)/)?[A-Z][A-Z0-9_]+-[0-9]+"),
+	Entry("lower-case keys", []string{"feature/abc-1"}, "(?i)^(feature)/[A-Z][A-Z0-9_]+-[0-9]+"),
+	Entry("lower-case keys of unknown projects", []string{"feature/xyz-1", "fix/ABC-2"}, "^(fix)/[A-Z][A-Z0-9_]+-[0-9]+"),
+	Entry("denied projects", []string{"feature/DEF-1", "fix/ABC-2"}, "^(fix)/[A-Z][A-Z0-9_]+-[0-9]+"),
+	Entry("identifiers shaped like keys", []string{"feature/utf-8"}, ""),
+)
+
+var _ = DescribeTable("detecting a commit format",
+	func(subjects []string, expected string) {
+		Expect(repoHistory{Subjects: subjects}.detectCommitFormat()).To(Equal(expected))
+	},
+	Entry("no keys", []string{"Initial commit"}, ""),
+	Entry("leading keys", []string{"ABC-1 Add", "ABC-2 Fix"}, "{key} {message}"),
+	Entry("colons win over plain keys", []string{"ABC-1: Add", "ABC-2: Fix", "ABC-3 Tidy"}, "{key}: {message}"),
+	Entry("trailing keys", []string{"Add login (ABC-1)"}, "{message} ({key})"),
+)
@@ -0,0 +1,45 @@
+package jitt
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("jitt validate with lint rules", func() {
+	var oldCwd string
+
+	BeforeEach(func() {
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+
+		initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"+
+			"lint:\n"+
+			"  conventional:\n    severity: error\n"+
+			"  subject_period:\n    severity: warning\n"), 0o600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should fail on errors with their position", func() {
+		session := runValidateMessage("ABC-1 Add pagination\n")
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring(
+			"❌ 1:7: subject does not follow Conventional Commits (type(scope): description) [conventional]"))
+	})
+
+	It("should print warnings without failing", func() {
+		session := runValidateMessage("feat: ABC-1 add pagination.\n")
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("⚠️  1:27: subject ends with a period [subject-period]"))
+	})
+})
@@ -0,0 +1,94 @@
+package jitt
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// ticketCommit is a commit with the ticket keys it references, whether in
+// the subject or in trailers
+type ticketCommit struct {
+	SHA     string
+	Subject string
+	Keys    []string
+}
+
+// HandleLog handles the 'jitt log' command
+func HandleLog(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("log", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	ticket := fs.String("ticket", "", "only show commits referencing this ticket")
+	limit := fs.Int("n", 20, "maximum number of commits to show (0 for all)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagError(err)
+	}
+
+	if len(positional) > 1 {
+		return usageError("Usage: jitt log [<range>] [--ticket ABC-123] [-n 20]")
+	}
+
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+
+	revs := []string{"HEAD"}
+	if len(positional) == 1 {
+		revs = positional
+	}
+	if *ticket == "" && *limit > 0 {
+		revs = append(revs, "-n", strconv.Itoa(*limit))
+	}
+
+	commits, err := ticketCommits(cfg, revs...)
+	if err != nil {
+		return failure("Error reading commits: %v", err)
+	}
+
+	w := tabwriter.NewWriter(stdio.Out, 0, 0, 2, ' ', 0)
+	shown := 0
+	for _, c := range commits {
+		if *ticket != "" && !containsString(c.Keys, strings.ToUpper(*ticket)) {
+			continue
+		}
+		if *limit > 0 && shown == *limit {
+			break
+		}
+		shown++
+
+		keys := "-"
+		if len(c.Keys) > 0 {
+			keys = strings.Join(c.Keys, ",")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.SHA[:7], keys, c.Subject)
+	}
+	_ = w.Flush()
+	return nil
+}
+
+// ticketCommits lists the commits selected by revs, newest first, with their
+// subjects stripped of ticket keys
+func ticketCommits(cfg *config.Config, revs ...string) ([]ticketCommit, error) {
+	commits, err := commitsInRange(revs...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]ticketCommit, 0, len(commits))
+	for _, c := range commits {
+		subject, _, _ := strings.Cut(c.Message, "\n")
+		keys := lib.TicketKeys(cfg, c.Message)
+		if text := lib.StripKeys(subject, keys); text != "" {
+			subject = text
+		}
+		result = append(result, ticketCommit{SHA: c.SHA, Subject: subject, Keys: keys})
+	}
+	return result, nil
+}
@@ -0,0 +1,91 @@
+package jitt
+
+import (
+	"os"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("jitt log and changelog commands", func() {
+	var oldCwd string
+
+	run := func(args ...string) *gexec.Session {
+		command := exec.Command(pathToJittBinary, args...)
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		return session
+	}
+
+	BeforeEach(func() {
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+
+		initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"), 0o600)).To(Succeed())
+		commit("ABC-1 Initial commit")
+		git("tag", "v1.0.0")
+		commit("[ABC-2] Add login form")
+		commit("Style the login form\n\nRefs: ABC-2")
+		commit("Bump dependencies")
+		commit("ABC-3 Fix logout")
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	Describe("log", func() {
+		It("should list commits with keys from subjects and trailers", func() {
+			session := run("log", "-n", "4")
+
+			Eventually(session).Should(gexec.Exit(0))
+			output := string(session.Out.Contents())
+			Expect(output).To(MatchRegexp(`(?m)^[0-9a-f]{7}  ABC-3  Fix logout$`))
+			Expect(output).To(MatchRegexp(`(?m)^[0-9a-f]{7}  -      Bump dependencies$`))
+			Expect(output).To(MatchRegexp(`(?m)^[0-9a-f]{7}  ABC-2  Style the login form$`))
+			Expect(output).To(MatchRegexp(`(?m)^[0-9a-f]{7}  ABC-2  Add login form$`))
+			Expect(output).NotTo(ContainSubstring("Initial commit"))
+		})
+
+		It("should filter by ticket", func() {
+			session := run("log", "--ticket", "abc-2")
+
+			Eventually(session).Should(gexec.Exit(0))
+			output := string(session.Out.Contents())
+			Expect(output).To(ContainSubstring("Add login form"))
+			Expect(output).To(ContainSubstring("Style the login form"))
+			Expect(output).NotTo(ContainSubstring("Fix logout"))
+		})
+	})
+
+	Describe("changelog", func() {
+		It("should group commits since the last tag by ticket", func() {
+			session := run("changelog")
+
+			Eventually(session).Should(gexec.Exit(0))
+			output := string(session.Out.Contents())
+			Expect(output).To(HavePrefix("## Changes (v1.0.0..HEAD)\n"))
+			Expect(output).To(MatchRegexp(`### ABC-2\n\n- Add login form \([0-9a-f]{7}\)\n- Style the login form \([0-9a-f]{7}\)\n`))
+			Expect(output).To(MatchRegexp(`### ABC-3\n\n- Fix logout \([0-9a-f]{7}\)\n`))
+			Expect(output).To(MatchRegexp(`### Other changes\n\n- Bump dependencies \([0-9a-f]{7}\)\n`))
+			Expect(output).NotTo(ContainSubstring("ABC-1"))
+		})
+
+		It("should add ticket summaries from Jira", func() {
+			fake := newFakeJira()
+			defer fake.Close()
+			fake.addIssue("ABC-2", "Done")
+			Expect(fake.writeConfig("")).To(Succeed())
+
+			session := run("changelog", "v1.0.0..HEAD", "--summaries")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("### ABC-2: Summary of ABC-2"))
+		})
+	})
+})
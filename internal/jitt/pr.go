@@ -0,0 +1,242 @@
+package jitt
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// defaultPRTemplate is used unless pr.template or --template points elsewhere
+const defaultPRTemplate = `{{ with .Ticket -}}
+## {{ .Key }}{{ with .Summary }}: {{ . }}{{ end }}
+{{ with .URL }}
+{{ . }}
+{{ end }}
+{{- with .Excerpt }}
+### Description
+
+{{ . }}
+{{ end }}
+{{- with .AcceptanceCriteria }}
+### Acceptance criteria
+
+{{ . }}
+{{ end }}
+{{ end -}}
+### Changes
+
+{{ range .Commits }}- {{ .Subject }} ({{ .ShortSHA }})
+{{ else }}_No commits since {{ .Base }}._
+{{ end }}`
+
+// acceptanceHeading matches a heading introducing acceptance criteria in a
+// description, in wiki markup (h3. Acceptance Criteria), Markdown (### ...)
+// or plain text (Acceptance criteria:)
+var acceptanceHeading = regexp.MustCompile(`(?im)^\s*(?:h[1-6]\.\s*|#{1,6}\s*|\*)?acceptance criteria\*?:?\s*$`)
+
+// headingLine matches the start of any other section
+var headingLine = regexp.MustCompile(`(?m)^\s*(?:h[1-6]\.\s|#{1,6}\s)`)
+
+// prData is what PR templates are rendered with
+type prData struct {
+	Branch  string
+	Base    string
+	Ticket  *prTicket
+	Commits []prCommit
+}
+
+// prTicket is the ticket a pull request is for
+type prTicket struct {
+	Key                string
+	Summary            string
+	Status             string
+	URL                string
+	Description        string
+	Excerpt            string
+	AcceptanceCriteria string
+}
+
+// prCommit is a commit on the pull request branch
+type prCommit struct {
+	SHA      string
+	ShortSHA string
+	Subject  string
+	Body     string
+	Keys     []string
+}
+
+// HandlePR handles the 'jitt pr' command
+func HandlePR(stdio IO, args []string) error {
+	if len(args) == 0 || args[0] != "describe" {
+		return usageError("Usage: jitt pr describe [--base <branch>] [--template <file>] [--output <file>]")
+	}
+
+	fs := flag.NewFlagSet("pr describe", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	base := fs.String("base", "", "branch the pull request targets (default: workflow.main_branch)")
+	templatePath := fs.String("template", "", "text/template file to render (default: pr.template or built-in)")
+	output := fs.String("output", "", "write the description to this file instead of stdout")
+	if _, err := parseFlags(fs, args[1:]); err != nil {
+		return flagError(err)
+	}
+
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+	if *base == "" {
+		*base = cfg.Workflow.MainBranch
+	}
+	if *templatePath == "" {
+		*templatePath = cfg.PR.Template
+	}
+
+	tmpl, err := loadPRTemplate(*templatePath)
+	if err != nil {
+		return failure("Error loading template: %v", err)
+	}
+
+	data, err := buildPRData(context.Background(), stdio.Err, cfg, *base)
+	if err != nil {
+		return failure("Error: %v", err)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return failure("Error rendering template: %v", err)
+	}
+
+	if *output == "" {
+		fmt.Fprint(stdio.Out, body.String())
+		return nil
+	}
+	if err := os.WriteFile(*output, body.Bytes(), 0o600); err != nil {
+		return failure("Error writing %s: %v", *output, err)
+	}
+	fmt.Fprintf(stdio.Out, "PR description written to %s\n", *output)
+	return nil
+}
+
+func loadPRTemplate(path string) (*template.Template, error) {
+	text := defaultPRTemplate
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	}
+	return template.New("pr").Parse(text)
+}
+
+func buildPRData(ctx context.Context, warn io.Writer, cfg *config.Config, base string) (*prData, error) {
+	branch, err := currentBranch()
+	if err != nil {
+		return nil, fmt.Errorf("not on a branch: %w", err)
+	}
+
+	commits, err := commitsInRange("--reverse", base+"..HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("listing commits since %s: %w", base, err)
+	}
+
+	data := &prData{Branch: branch, Base: base}
+	var messages []string
+	for _, c := range commits {
+		subject, body, _ := strings.Cut(c.Message, "\n")
+		data.Commits = append(data.Commits, prCommit{
+			SHA:      c.SHA,
+			ShortSHA: c.SHA[:7],
+			Subject:  subject,
+			Body:     strings.TrimSpace(body),
+			Keys:     lib.TicketKeys(cfg, c.Message),
+		})
+		messages = append(messages, c.Message)
+	}
+
+	keys := lib.BranchKeys(cfg, branch)
+	if len(keys) == 0 {
+		keys = lib.TicketKeys(cfg, strings.Join(messages, "\n"))
+	}
+	if len(keys) > 0 {
+		data.Ticket = describeTicket(ctx, warn, cfg, keys[0])
+	}
+	return data, nil
+}
+
+// describeTicket gathers what a PR description needs from Jira. Without Jira,
+// or when it can't be reached, only the key is filled in and the problem is
+// reported to warn.
+func describeTicket(ctx context.Context, warn io.Writer, cfg *config.Config, key string) *prTicket {
+	ticket := &prTicket{Key: key}
+	client, err := newJiraClient(cfg)
+	if err != nil {
+		return ticket
+	}
+	ticket.URL = client.BrowseURL(key)
+
+	var extra []string
+	if cfg.Jira.AcceptanceCriteriaField != "" {
+		extra = append(extra, cfg.Jira.AcceptanceCriteriaField)
+	}
+	issue, err := client.Issue(ctx, key, extra...)
+	if err != nil {
+		fmt.Fprintf(warn, "jitt: could not fetch %s: %v\n", key, err)
+		return ticket
+	}
+
+	ticket.Summary = issue.Fields.Summary
+	ticket.Status = issue.Fields.Status.Name
+	ticket.Description = issue.Fields.Description
+
+	description, criteria := splitAcceptanceCriteria(issue.Fields.Description)
+	if field := issue.Fields.Extra[cfg.Jira.AcceptanceCriteriaField]; field != "" {
+		criteria = strings.TrimSpace(field)
+	}
+	ticket.AcceptanceCriteria = criteria
+	ticket.Excerpt = excerpt(description, cfg.PR.ExcerptLength)
+	return ticket
+}
+
+// splitAcceptanceCriteria separates an "Acceptance criteria" section from the
+// rest of a description
+func splitAcceptanceCriteria(description string) (rest, criteria string) {
+	loc := acceptanceHeading.FindStringIndex(description)
+	if loc == nil {
+		return strings.TrimSpace(description), ""
+	}
+
+	before, section := description[:loc[0]], description[loc[1]:]
+	after := ""
+	if next := headingLine.FindStringIndex(section); next != nil {
+		section, after = section[:next[0]], section[next[0]:]
+	}
+	return strings.TrimSpace(before + after), strings.TrimSpace(section)
+}
+
+// excerpt shortens text to at most limit characters, cutting at a word boundary
+func excerpt(text string, limit int) string {
+	text = strings.TrimSpace(text)
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return text
+	}
+
+	cut := runes[:limit]
+	for i := len(cut) - 1; i > limit/2; i-- {
+		if cut[i] == ' ' || cut[i] == '\n' {
+			cut = cut[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(string(cut)) + "…"
+}
@@ -0,0 +1,158 @@
+package jitt
+
+import (
+	"os"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+func runPRCommand(args ...string) *gexec.Session {
+	command := exec.Command(pathToJittBinary, append([]string{"pr"}, args...)...)
+	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+	return session
+}
+
+var _ = DescribeTable("excerpt",
+	func(text string, limit int, expected string) {
+		Expect(excerpt(text, limit)).To(Equal(expected))
+	},
+	Entry("keeps short text", "fix the login form", 20, "fix the login form"),
+	Entry("cuts at a word boundary", "fix the login form for good", 20, "fix the login form…"),
+	Entry("counts characters, not bytes", "äöüß äöüßäöüßäöüß", 12, "äöüß äöüßäöü…"),
+	Entry("cuts multibyte text at a word boundary", "Ändere die Überschrift für Größen", 24, "Ändere die Überschrift…"),
+)
+
+var _ = Describe("jitt pr command", func() {
+	var (
+		tmpDir string
+		oldCwd string
+		fake   *fakeJira
+	)
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		initGitRepo()
+		fake = newFakeJira()
+		fake.addIssue("ABC-1", "In Review")
+		fake.describe("ABC-1", "Users cannot log in with SSO.\n\nh3. Acceptance Criteria\n"+
+			"* SSO login works\n* Errors are shown\n\nh3. Notes\nSee the runbook.", nil)
+
+		commit("Initial commit")
+		git("checkout", "-q", "-b", "feature/ABC-1-sso")
+		commit("ABC-1 add SSO button\n\nDetails here.")
+		commit("ABC-1 handle callback errors")
+	})
+
+	AfterEach(func() {
+		fake.Close()
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should show usage without a subcommand", func() {
+		session := runPRCommand()
+
+		Eventually(session).Should(gexec.Exit(ExitUsage))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("Usage: jitt pr describe"))
+	})
+
+	Context("with Jira configured", func() {
+		BeforeEach(func() {
+			Expect(fake.writeConfig("")).To(Succeed())
+		})
+
+		It("should describe the ticket and list the branch commits", func() {
+			session := runPRCommand("describe")
+
+			Eventually(session).Should(gexec.Exit(0))
+			output := string(session.Out.Contents())
+			Expect(output).To(HavePrefix("## ABC-1: Summary of ABC-1\n"))
+			Expect(output).To(ContainSubstring(fake.server.URL + "/browse/ABC-1"))
+			Expect(output).To(ContainSubstring("### Description\n\nUsers cannot log in with SSO.\n\nh3. Notes\nSee the runbook."))
+			Expect(output).To(ContainSubstring("### Acceptance criteria\n\n* SSO login works\n* Errors are shown\n"))
+			Expect(output).To(MatchRegexp(`### Changes\n\n- ABC-1 add SSO button \([0-9a-f]{7}\)\n- ABC-1 handle callback errors`))
+			Expect(output).NotTo(ContainSubstring("Initial commit"))
+		})
+
+		It("should write the description to a file", func() {
+			session := runPRCommand("describe", "--output", "pr.md")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("PR description written to pr.md"))
+			Expect(os.ReadFile("pr.md")).To(ContainSubstring("## ABC-1: Summary of ABC-1"))
+		})
+
+		It("should use a repository template", func() {
+			Expect(os.WriteFile("pr-template.md", []byte(
+				"{{ .Ticket.Key }} on {{ .Branch }} into {{ .Base }}: {{ len .Commits }} commits\n"), 0o600)).To(Succeed())
+			Expect(fake.writeConfig("pr:\n  template: pr-template.md\n")).To(Succeed())
+
+			session := runPRCommand("describe")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(Equal("ABC-1 on feature/ABC-1-sso into main: 2 commits\n"))
+		})
+
+		It("should truncate long descriptions", func() {
+			Expect(fake.writeConfig("pr:\n  excerpt_length: 20\n")).To(Succeed())
+
+			session := runPRCommand("describe")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("Users cannot log in…"))
+		})
+
+		It("should report template errors", func() {
+			Expect(os.WriteFile("broken.md", []byte("{{ .Nope "), 0o600)).To(Succeed())
+
+			session := runPRCommand("describe", "--template", "broken.md")
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("Error loading template"))
+		})
+	})
+
+	Context("with an acceptance criteria custom field", func() {
+		BeforeEach(func() {
+			fake.describe("ABC-1", "Plain description.", map[string]string{"customfield_10035": "Given SSO, it works"})
+			Expect(fake.writeConfig("")).To(Succeed())
+			f, err := os.OpenFile(".jitt.yaml", os.O_APPEND|os.O_WRONLY, 0o600)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = f.WriteString("  acceptance_criteria_field: customfield_10035\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f.Close()).To(Succeed())
+		})
+
+		It("should use the field's value", func() {
+			session := runPRCommand("describe")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("### Acceptance criteria\n\nGiven SSO, it works\n"))
+		})
+	})
+
+	Context("without Jira", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC"), 0o600)).To(Succeed())
+		})
+
+		It("should still list the ticket key and commits", func() {
+			session := runPRCommand("describe")
+
+			Eventually(session).Should(gexec.Exit(0))
+			output := string(session.Out.Contents())
+			Expect(output).To(HavePrefix("## ABC-1\n"))
+			Expect(output).NotTo(ContainSubstring("### Description"))
+			Expect(output).To(ContainSubstring("- ABC-1 handle callback errors"))
+		})
+	})
+})
@@ -0,0 +1,461 @@
+package jitt
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	gitrepo "github.com/bbommarito/jitt/internal/git"
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// checkResult is the outcome of checking one commit, or with no SHA, the branch name
+type checkResult struct {
+	SHA      string
+	Name     string
+	Problems []lib.Problem
+	// Bypass is the reason the commit gave for skipping the checks
+	Bypass string
+	// Exempt is why validate.exempt excused the commit from the checks
+	Exempt string
+}
+
+// errorCount returns the number of problems with error severity
+func (r checkResult) errorCount() int {
+	n := 0
+	for _, p := range r.Problems {
+		if p.Severity != config.SeverityWarning {
+			n++
+		}
+	}
+	return n
+}
+
+// label identifies the checked item in reports: "abc1234 Subject" or "branch feature/x"
+func (r checkResult) label() string {
+	if r.SHA == "" {
+		return r.Name
+	}
+	return r.SHA[:7] + " " + r.Name
+}
+
+// checkCommits validates the message of every commit revs select, oldest
+// first, against the configuration scopes finds for the files it touches.
+// Bypasses are judged against branch, where the commits are landing.
+func checkCommits(ctx context.Context, warn io.Writer, cfg *config.Config, scopes scopeFunc, branch string, revs ...string) ([]checkResult, error) {
+	commits, err := commitsInRange(append([]string{"--reverse"}, revs...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]checkResult, 0, len(commits))
+	for _, c := range commits {
+		// A merge touches what it brings into the branch: its first-parent diff
+		args := []string{"diff-tree", "--no-commit-id", "--name-only", "-r", "-z", "--root", c.SHA}
+		if c.IsMerge() {
+			args = []string{"diff-tree", "--name-only", "-r", "-z", c.Parents[0], c.SHA}
+		}
+		files, err := runGit(args...)
+		if err != nil {
+			return nil, err
+		}
+		result := checkMessage(ctx, warn, cfg, scopes, branch, c.Message, lib.CommitInfo{
+			AuthorEmail:    c.AuthorEmail,
+			CommitterEmail: c.CommitterEmail,
+			Files:          gitrepo.SplitNUL(files),
+			Merge:          c.IsMerge(),
+		})
+		result.SHA, result.Name = c.SHA, c.Subject()
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// checkMessage validates the message of a commit landing on branch against
+// the configuration scopes finds for the files it touches. Commits that
+// validate.exempt excuses aren't checked, and neither are those carrying a
+// bypass reason, where bypasses are allowed.
+func checkMessage(ctx context.Context, warn io.Writer, cfg *config.Config, scopes scopeFunc, branch, message string, commit lib.CommitInfo) checkResult {
+	if reason, ok := lib.ExemptReason(cfg, commit, message); ok {
+		return checkResult{Exempt: reason}
+	}
+	reason, bypassed := lib.BypassReason(message)
+	if !bypassed {
+		return checkResult{Problems: validateScopes(ctx, warn, cfg, scopes, message, commit.Files)}
+	}
+	if !lib.BypassAllowed(cfg, branch) {
+		return checkResult{Bypass: reason, Problems: []lib.Problem{bypassProblem(branch)}}
+	}
+	return checkResult{Bypass: reason}
+}
+
+// countProblems returns the total number of errors and warnings in results
+func countProblems(results []checkResult) (errors, warnings int) {
+	for _, r := range results {
+		e := r.errorCount()
+		errors += e
+		warnings += len(r.Problems) - e
+	}
+	return errors, warnings
+}
+
+// writeTextReport prints the problems of each result for people reading a console
+func writeTextReport(w io.Writer, results []checkResult) {
+	for _, r := range results {
+		if r.Exempt != "" {
+			fmt.Fprintf(w, "%s\n  ⏭️  exempt: %s\n", r.label(), r.Exempt)
+			continue
+		}
+		if r.Bypass != "" && len(r.Problems) == 0 {
+			fmt.Fprintf(w, "%s\n  ⏭️  checks skipped: %s\n", r.label(), r.Bypass)
+			continue
+		}
+		if len(r.Problems) == 0 {
+			continue
+		}
+		fmt.Fprintln(w, r.label())
+		for _, p := range r.Problems {
+			icon := "❌"
+			if p.Severity == config.SeverityWarning {
+				icon = "⚠️ "
+			}
+			fmt.Fprintf(w, "  %s %s\n", icon, p)
+		}
+	}
+
+	errors, warnings := countProblems(results)
+	fmt.Fprintf(w, "Checked %d items: %d errors, %d warnings\n", len(results), errors, warnings)
+}
+
+// writeGitHubAnnotations prints workflow commands that GitHub Actions turns
+// into annotations on the run and pull request
+func writeGitHubAnnotations(w io.Writer, results []checkResult) {
+	for _, r := range results {
+		for _, p := range r.Problems {
+			command := "error"
+			if p.Severity == config.SeverityWarning {
+				command = "warning"
+			}
+			fmt.Fprintf(w, "::%s title=%s::%s\n", command,
+				escapeGitHubProperty("jitt "+p.Rule), escapeGitHubData(r.label()+": "+p.String()))
+		}
+	}
+}
+
+func escapeGitHubData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+// gitLabIssue is one entry of a GitLab code quality report
+type gitLabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitLabLocation `json:"location"`
+}
+
+type gitLabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitLabLines `json:"lines"`
+}
+
+type gitLabLines struct {
+	Begin int `json:"begin"`
+}
+
+// writeGitLabCodeQuality writes a GitLab code quality report. Commit messages
+// aren't files, so findings are attached to .jitt.yaml, which defines the rules.
+func writeGitLabCodeQuality(w io.Writer, results []checkResult) error {
+	issues := []gitLabIssue{}
+	for _, r := range results {
+		for _, p := range r.Problems {
+			severity := "major"
+			if p.Severity == config.SeverityWarning {
+				severity = "minor"
+			}
+			description := r.label() + ": " + p.String()
+			sum := sha256.Sum256([]byte(r.SHA + "\x00" + r.Name + "\x00" + description))
+			issues = append(issues, gitLabIssue{
+				Description: description,
+				CheckName:   p.Rule,
+				Fingerprint: hex.EncodeToString(sum[:]),
+				Severity:    severity,
+				Location:    gitLabLocation{Path: config.FileName, Lines: gitLabLines{Begin: 1}},
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(issues)
+}
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// writeJUnit writes a JUnit XML report with one test case per commit (and
+// the branch); errors fail the test case, warnings go to its output
+func writeJUnit(w io.Writer, results []checkResult) error {
+	suite := junitSuite{Name: "jitt", Tests: len(results)}
+	for _, r := range results {
+		tc := junitCase{ClassName: "jitt.commits", Name: r.label()}
+		if r.SHA == "" {
+			tc.ClassName = "jitt.branch"
+		}
+
+		var errors, warnings []string
+		var first lib.Problem
+		for _, p := range r.Problems {
+			if p.Severity == config.SeverityWarning {
+				warnings = append(warnings, p.String())
+				continue
+			}
+			if len(errors) == 0 {
+				first = p
+			}
+			errors = append(errors, p.String())
+		}
+
+		if len(errors) > 0 {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: first.Message, Type: first.Rule, Text: strings.Join(errors, "\n")}
+		}
+		if len(warnings) > 0 {
+			tc.SystemOut = "warnings:\n" + strings.Join(warnings, "\n")
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(junitSuites{Suites: []junitSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ruleDescriptions describes each rule ID for reports that list their rules
+var ruleDescriptions = map[string]string{
+	"config":            "The .jitt.yaml files applying to the commit can be read",
+	"ticket-reference":  "Commit messages reference a Jira ticket",
+	"commit-format":     "Commit messages follow commit.format",
+	"smart-commits":     "Smart commit commands are valid",
+	"conventional":      "Subjects follow Conventional Commits",
+	"subject-length":    "Subjects are not too long",
+	"imperative-mood":   "Subjects use the imperative mood",
+	"subject-period":    "Subjects don't end with a period",
+	"blank-line":        "A blank line separates subject and body",
+	"body-wrap":         "Body lines are wrapped",
+	"forbidden-words":   "Messages avoid forbidden words",
+	"required-trailers": "Messages have the required trailers",
+	"branch-pattern":    "Branch names match branch.pattern",
+	"bypass":            "Commits only skip the checks where bypasses are allowed",
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// writeSARIF writes a SARIF 2.1.0 log. Like the GitLab report, results point
+// at .jitt.yaml; the commit (or branch) is given as their logical location.
+func writeSARIF(w io.Writer, results []checkResult) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "jitt",
+			InformationURI: "https://github.com/bbommarito/jitt",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range results {
+		location := sarifLogicalLocation{Name: r.label(), FullyQualifiedName: r.SHA, Kind: "commit"}
+		if r.SHA == "" {
+			location = sarifLogicalLocation{Name: r.Name, FullyQualifiedName: r.Name, Kind: "branch"}
+		}
+
+		for _, p := range r.Problems {
+			if !seen[p.Rule] {
+				seen[p.Rule] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules,
+					sarifRule{ID: p.Rule, ShortDescription: sarifMessage{Text: ruleDescriptions[p.Rule]}})
+			}
+
+			level := "error"
+			if p.Severity == config.SeverityWarning {
+				level = "warning"
+			}
+			sum := sha256.Sum256([]byte(location.FullyQualifiedName + "\x00" + p.String()))
+			run.Results = append(run.Results, sarifResult{
+				RuleID:  p.Rule,
+				Level:   level,
+				Message: sarifMessage{Text: r.label() + ": " + p.String()},
+				Locations: []sarifLocation{{
+					PhysicalLocation: sarifPhysicalLocation{
+						ArtifactLocation: sarifArtifactLocation{URI: config.FileName},
+						Region:           sarifRegion{StartLine: 1},
+					},
+					LogicalLocations: []sarifLogicalLocation{location},
+				}},
+				PartialFingerprints: map[string]string{"jitt/v1": hex.EncodeToString(sum[:])},
+			})
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
+
+type checkstyleReport struct {
+	XMLName xml.Name         `xml:"checkstyle"`
+	Version string           `xml:"version,attr"`
+	Files   []checkstyleFile `xml:"file"`
+}
+
+type checkstyleFile struct {
+	Name   string            `xml:"name,attr"`
+	Errors []checkstyleError `xml:"error"`
+}
+
+type checkstyleError struct {
+	Line     int    `xml:"line,attr"`
+	Column   int    `xml:"column,attr,omitempty"`
+	Severity string `xml:"severity,attr"`
+	Message  string `xml:"message,attr"`
+	Source   string `xml:"source,attr"`
+}
+
+// writeCheckstyle writes a Checkstyle XML report with one <file> per commit
+// (and the branch); lines and columns are positions in the commit message
+func writeCheckstyle(w io.Writer, results []checkResult) error {
+	report := checkstyleReport{Version: "4.3"}
+	for _, r := range results {
+		file := checkstyleFile{Name: r.label()}
+		for _, p := range r.Problems {
+			severity := "error"
+			if p.Severity == config.SeverityWarning {
+				severity = "warning"
+			}
+			file.Errors = append(file.Errors, checkstyleError{
+				Line:     max(p.Line, 1),
+				Column:   p.Column,
+				Severity: severity,
+				Message:  p.Message,
+				Source:   "jitt." + p.Rule,
+			})
+		}
+		report.Files = append(report.Files, file)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,110 @@
+package jitt
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("jitt validate --range", func() {
+	var oldCwd string
+
+	runValidateRange := func(args ...string) *gexec.Session {
+		command := exec.Command(pathToJittBinary, append([]string{"validate", "--range", "main..HEAD"}, args...)...)
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		return session
+	}
+
+	BeforeEach(func() {
+		tmpDir := GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"+
+			"lint:\n  subject_period:\n    severity: warning\n"), 0o600)).To(Succeed())
+		commit("ABC-1 Initial commit")
+		git("checkout", "-q", "-b", "feature")
+		commit("ABC-2 Add login form.")
+		commit("Style the login form")
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should print the problems of every commit", func() {
+		session := runValidateRange()
+
+		Eventually(session).Should(gexec.Exit(1))
+		output := string(session.Out.Contents())
+		Expect(output).To(ContainSubstring("1:21: subject ends with a period [subject-period]"))
+		Expect(output).To(ContainSubstring("Checked 2 items: 1 errors, 1 warnings"))
+	})
+
+	It("should write a SARIF report", func() {
+		session := runValidateRange("--report", "sarif", "--report-file", "out.sarif")
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("Checked 2 items"))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("Wrote report to out.sarif"))
+
+		data, err := os.ReadFile("out.sarif")
+		Expect(err).NotTo(HaveOccurred())
+		var log sarifLog
+		Expect(json.Unmarshal(data, &log)).To(Succeed())
+		Expect(log.Version).To(Equal("2.1.0"))
+		Expect(log.Runs).To(HaveLen(1))
+		Expect(log.Runs[0].Tool.Driver.Rules).To(ConsistOf(
+			sarifRule{ID: "subject-period", ShortDescription: sarifMessage{Text: "Subjects don't end with a period"}},
+			sarifRule{ID: "ticket-reference", ShortDescription: sarifMessage{Text: "Commit messages reference a Jira ticket"}},
+		))
+
+		results := log.Runs[0].Results
+		Expect(results).To(HaveLen(2))
+		Expect(results[0].RuleID).To(Equal("subject-period"))
+		Expect(results[0].Level).To(Equal("warning"))
+		Expect(results[1].RuleID).To(Equal("ticket-reference"))
+		Expect(results[1].Level).To(Equal("error"))
+		Expect(results[1].Locations[0].LogicalLocations[0].FullyQualifiedName).To(Equal(git("rev-parse", "HEAD")))
+	})
+
+	It("should write a checkstyle report", func() {
+		Eventually(runValidateRange("--report", "checkstyle")).Should(gexec.Exit(1))
+
+		data, err := os.ReadFile("jitt-checkstyle.xml")
+		Expect(err).NotTo(HaveOccurred())
+		var report checkstyleReport
+		Expect(xml.Unmarshal(data, &report)).To(Succeed())
+		Expect(report.Files).To(HaveLen(2))
+		Expect(report.Files[0].Errors).To(Equal([]checkstyleError{{
+			Line: 1, Column: 21, Severity: "warning", Message: "subject ends with a period", Source: "jitt.subject-period",
+		}}))
+		Expect(report.Files[1].Errors).To(HaveLen(1))
+		Expect(report.Files[1].Errors[0].Source).To(Equal("jitt.ticket-reference"))
+	})
+
+	It("should write a JUnit report", func() {
+		Eventually(runValidateRange("--report", "junit")).Should(gexec.Exit(1))
+
+		data, err := os.ReadFile("jitt-junit.xml")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`<testsuite name="jitt" tests="2" failures="1">`))
+	})
+
+	It("should reject unknown report formats", func() {
+		session := runValidateRange("--report", "pdf")
+
+		Eventually(session).Should(gexec.Exit(ExitUsage))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("Unknown report format: pdf"))
+	})
+})
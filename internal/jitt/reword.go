@@ -0,0 +1,238 @@
+package jitt
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// rewordCommit is a commit of the range being rewritten, with everything
+// commit-tree needs to recreate it
+type rewordCommit struct {
+	SHA       string
+	Tree      string
+	Parents   []string
+	AuthorEnv []string
+	Message   string
+}
+
+// HandleReword handles the 'jitt reword' command
+func HandleReword(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("reword", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	ticket := fs.String("ticket", "", "ticket key to add (default: the ticket in the branch name)")
+	force := fs.Bool("force", false, "rewrite commits that were already pushed")
+	dryRun := fs.Bool("dry-run", false, "show the new messages without rewriting anything")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagError(err)
+	}
+
+	if len(positional) != 1 {
+		return usageError("Usage: jitt reword <range|base> [--ticket ABC-123] [--force] [--dry-run]")
+	}
+
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+
+	branch, err := currentBranch()
+	if err != nil {
+		return failure("Error: reword needs a checked out branch (HEAD is detached)")
+	}
+
+	key := strings.ToUpper(*ticket)
+	if key == "" {
+		key = branchTicket(cfg, branch)
+	}
+	if !lib.IsKey(key) {
+		return usageError("Error: no ticket to add - pass --ticket ABC-123 or use a branch named after the ticket")
+	}
+
+	rangeSpec := positional[0]
+	if !strings.Contains(rangeSpec, "..") {
+		rangeSpec += "..HEAD"
+	}
+
+	if err := reword(stdio.Out, cfg, branch, rangeSpec, key, *force, *dryRun); err != nil {
+		return failure("Error: %v", err)
+	}
+	return nil
+}
+
+// branchTicket returns the first ticket key in a branch name
+func branchTicket(cfg *config.Config, branch string) string {
+	keys := lib.BranchKeys(cfg, branch)
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[0]
+}
+
+// reword adds key to the messages of the commits in rangeSpec that reference
+// no ticket (in the way commit.position expects), recreating them (and
+// everything after them) with commit-tree so authorship, dates and trees are
+// kept, then moves branch to the new tip. The old tip is kept under
+// refs/jitt/backup/.
+func reword(out io.Writer, cfg *config.Config, branch, rangeSpec, key string, force, dryRun bool) error {
+	head, err := runGit("rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	commits, err := rewordCommits(rangeSpec)
+	if err != nil {
+		return err
+	}
+	if len(commits) == 0 {
+		fmt.Fprintf(out, "No commits in %s\n", rangeSpec)
+		return nil
+	}
+	if commits[len(commits)-1].SHA != head {
+		return fmt.Errorf("%s must end at HEAD (%s), the tip of %s", rangeSpec, head[:7], branch)
+	}
+
+	messages := make(map[string]string)
+	for _, c := range commits {
+		if len(lib.ReferencedKeys(cfg, c.Message)) > 0 {
+			continue
+		}
+		fixed, err := lib.AddKey(cfg, c.Message, key)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.SHA[:7], err)
+		}
+		messages[c.SHA] = fixed
+	}
+	if len(messages) == 0 {
+		fmt.Fprintf(out, "All %d commits in %s already reference a ticket\n", len(commits), rangeSpec)
+		return nil
+	}
+
+	if !force {
+		pushed, err := pushedRewrites(commits, messages, rangeSpec)
+		if err != nil {
+			return err
+		}
+		if len(pushed) > 0 {
+			return fmt.Errorf("%d commits to rewrite are already on a remote (%s); rewriting them "+
+				"would diverge from what others have - use --force to rewrite anyway", len(pushed), strings.Join(pushed, ", "))
+		}
+	}
+
+	if dryRun {
+		for _, c := range commits {
+			if message, ok := messages[c.SHA]; ok {
+				subject, _, _ := strings.Cut(message, "\n")
+				fmt.Fprintf(out, "[dry-run] would reword %s: %s\n", c.SHA[:7], subject)
+			}
+		}
+		return nil
+	}
+
+	rewritten := make(map[string]string)
+	var tip string
+	for _, c := range commits {
+		message, changed := messages[c.SHA]
+		if !changed {
+			message = c.Message
+		}
+
+		args := []string{"commit-tree", c.Tree}
+		parentsChanged := false
+		for _, parent := range c.Parents {
+			if p, ok := rewritten[parent]; ok {
+				parent = p
+				parentsChanged = true
+			}
+			args = append(args, "-p", parent)
+		}
+		if !changed && !parentsChanged {
+			tip = c.SHA
+			continue
+		}
+
+		sha, err := runGitInput(c.AuthorEnv, message+"\n", args...)
+		if err != nil {
+			return fmt.Errorf("rewriting %s: %w", c.SHA[:7], err)
+		}
+		rewritten[c.SHA] = sha
+		tip = sha
+
+		if changed {
+			subject, _, _ := strings.Cut(message, "\n")
+			fmt.Fprintf(out, "Reworded %s → %s: %s\n", c.SHA[:7], sha[:7], subject)
+		}
+	}
+
+	backup := fmt.Sprintf("refs/jitt/backup/%s/%d", branch, time.Now().Unix())
+	if _, err := runGit("update-ref", "-m", "jitt reword: backup", backup, head); err != nil {
+		return fmt.Errorf("creating backup ref: %w", err)
+	}
+	if _, err := runGit("update-ref", "-m", "jitt reword: add "+key, "refs/heads/"+branch, tip, head); err != nil {
+		return fmt.Errorf("updating %s: %w", branch, err)
+	}
+
+	fmt.Fprintf(out, "Rewrote %d commits on %s. Previous tip saved as %s\n", len(rewritten), branch, backup)
+	fmt.Fprintf(out, "To undo: git update-ref refs/heads/%s %s\n", branch, backup)
+	return nil
+}
+
+// rewordCommits lists the commits in rangeSpec, parents before children
+func rewordCommits(rangeSpec string) ([]rewordCommit, error) {
+	out, err := runGit("log", "--reverse", "--topo-order", "--date=raw",
+		"--format=%H%x1f%T%x1f%P%x1f%an%x1f%ae%x1f%ad%x1f%cn%x1f%ce%x1f%cd%x1f%B%x00", rangeSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []rewordCommit
+	for _, record := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 10)
+		if len(fields) != 10 {
+			continue
+		}
+		commits = append(commits, rewordCommit{
+			SHA:     fields[0],
+			Tree:    fields[1],
+			Parents: strings.Fields(fields[2]),
+			AuthorEnv: []string{
+				"GIT_AUTHOR_NAME=" + fields[3], "GIT_AUTHOR_EMAIL=" + fields[4], "GIT_AUTHOR_DATE=" + fields[5],
+				"GIT_COMMITTER_NAME=" + fields[6], "GIT_COMMITTER_EMAIL=" + fields[7], "GIT_COMMITTER_DATE=" + fields[8],
+			},
+			Message: strings.TrimRight(fields[9], "\n"),
+		})
+	}
+	return commits, nil
+}
+
+// pushedRewrites returns the short SHAs of commits that would be rewritten
+// (reworded ones and everything after the first) but are reachable from a
+// remote-tracking branch
+func pushedRewrites(commits []rewordCommit, messages map[string]string, rangeSpec string) ([]string, error) {
+	out, err := runGit("rev-list", rangeSpec, "--not", "--remotes")
+	if err != nil {
+		return nil, err
+	}
+	unpushed := make(map[string]bool)
+	for _, sha := range strings.Fields(out) {
+		unpushed[sha] = true
+	}
+
+	var pushed []string
+	rewriting := false
+	for _, c := range commits {
+		if _, ok := messages[c.SHA]; ok {
+			rewriting = true
+		}
+		if rewriting && !unpushed[c.SHA] {
+			pushed = append(pushed, c.SHA[:7])
+		}
+	}
+	return pushed, nil
+}
@@ -0,0 +1,135 @@
+package jitt
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("jitt reword command", func() {
+	var (
+		tmpDir string
+		oldCwd string
+	)
+
+	runReword := func(args ...string) *gexec.Session {
+		command := exec.Command(pathToJittBinary, append([]string{"reword"}, args...)...)
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		return session
+	}
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"), 0o600)).To(Succeed())
+		commit("ABC-1 Initial commit")
+		git("checkout", "-q", "-b", "feature/ABC-7-login")
+
+		cmd := exec.Command("git", "commit", "-q", "--allow-empty", "-m", "add login form",
+			"--author", "Jane Doe <jane@example.com>")
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE=2024-03-04T10:00:00+01:00", "GIT_COMMITTER_DATE=2024-03-05T11:00:00+01:00")
+		out, err := cmd.CombinedOutput()
+		Expect(err).NotTo(HaveOccurred(), string(out))
+
+		commit("ABC-8 style the login form")
+		commit("fix typo\n\nin the login form")
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should add the ticket to commits without one, keeping authorship and dates", func() {
+		oldHead := git("rev-parse", "HEAD")
+
+		session := runReword("main..HEAD", "--ticket", "abc-9")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("Rewrote 3 commits on feature/ABC-7-login"))
+
+		Expect(git("log", "--format=%s", "main..HEAD")).To(Equal("ABC-9 fix typo\nABC-8 style the login form\nABC-9 add login form"))
+		Expect(git("log", "-1", "--format=%b")).To(Equal("in the login form"))
+		Expect(git("log", "-1", "--format=%an <%ae> %aI %cI", "HEAD~2")).To(
+			Equal("Jane Doe <jane@example.com> 2024-03-04T10:00:00+01:00 2024-03-05T11:00:00+01:00"))
+
+		backups := git("for-each-ref", "--format=%(objectname)", "refs/jitt/backup/feature/ABC-7-login/")
+		Expect(backups).To(Equal(oldHead))
+		Expect(git("status", "--porcelain")).To(Equal("?? .jitt.yaml"))
+	})
+
+	It("should default to the ticket in the branch name and accept a base", func() {
+		Eventually(runReword("main")).Should(gexec.Exit(0))
+
+		Expect(git("log", "--format=%s", "main..HEAD")).To(Equal("ABC-7 fix typo\nABC-8 style the login form\nABC-7 add login form"))
+	})
+
+	It("should only show the new messages in dry-run mode", func() {
+		oldHead := git("rev-parse", "HEAD")
+
+		session := runReword("main..HEAD", "--dry-run")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("would reword"))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("ABC-7 add login form"))
+		Expect(git("rev-parse", "HEAD")).To(Equal(oldHead))
+	})
+
+	It("should add a --ticket of a project the config doesn't know", func() {
+		session := runReword("main..HEAD", "--ticket", "XYZ-5", "--dry-run")
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("XYZ-5 add login form"))
+
+		Eventually(runReword("main..HEAD", "--ticket", "XYZ-5")).Should(gexec.Exit(0))
+		Expect(git("log", "--format=%s", "main..HEAD")).To(Equal("XYZ-5 fix typo\nABC-8 style the login form\nXYZ-5 add login form"))
+	})
+
+	It("should add the --ticket as a trailer in trailer mode, ignoring keys in the body", func() {
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\ncommit:\n  position: trailer\n"), 0o600)).To(Succeed())
+		commit("tidy the form\n\nRelated to ABC-9")
+
+		Eventually(runReword("HEAD~1", "--ticket", "ABC-123")).Should(gexec.Exit(0))
+
+		Expect(git("log", "-1", "--format=%B")).To(Equal("tidy the form\n\nRelated to ABC-9\n\nRefs: ABC-123"))
+	})
+
+	It("should refuse ranges that don't end at HEAD", func() {
+		session := runReword("main..HEAD~1")
+
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("must end at HEAD"))
+	})
+
+	Context("when the commits were pushed", func() {
+		BeforeEach(func() {
+			remote := filepath.Join(GinkgoT().TempDir(), "remote.git")
+			git("init", "-q", "--bare", remote)
+			git("remote", "add", "origin", remote)
+			git("push", "-q", "origin", "HEAD")
+		})
+
+		It("should refuse without --force", func() {
+			oldHead := git("rev-parse", "HEAD")
+
+			session := runReword("main..HEAD")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("3 commits to rewrite are already on a remote"))
+			Expect(git("rev-parse", "HEAD")).To(Equal(oldHead))
+		})
+
+		It("should rewrite them with --force", func() {
+			Eventually(runReword("main..HEAD", "--force")).Should(gexec.Exit(0))
+
+			Expect(strings.Split(git("log", "--format=%s", "main..HEAD"), "\n")).To(HaveEach(HavePrefix("ABC-")))
+		})
+	})
+})
@@ -0,0 +1,226 @@
+package jitt
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	gitrepo "github.com/bbommarito/jitt/internal/git"
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+const serverHookUsage = "Usage: jitt server-hook pre-receive [--config <file>|--config-rev <rev>]\n" +
+	"       jitt server-hook update <ref> <old> <new> [--config <file>|--config-rev <rev>]"
+
+// ruleSource is the configuration a push is checked against
+type ruleSource struct {
+	cfg    *config.Config
+	scopes scopeFunc
+}
+
+// refUpdate is one ref a push changes, as git hands it to server-side hooks
+type refUpdate struct {
+	Ref string
+	Old string
+	New string
+}
+
+// HandleServerHook handles the 'jitt server-hook' command, run from the
+// pre-receive or update hook of the repository being pushed to. It checks the
+// commits each push adds to a branch, and the names of new branches, and
+// rejects the push when any break the rules.
+func HandleServerHook(stdio IO, args []string) error {
+	if len(args) == 0 {
+		return usageError(serverHookUsage)
+	}
+
+	fs := flag.NewFlagSet("server-hook "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	configFile := fs.String("config", "", "server-side .jitt.yaml to enforce instead of the one in the pushed tree")
+	configRev := fs.String("config-rev", "", "enforce the .jitt.yaml files committed at this revision, such as refs/heads/main")
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		return flagError(err)
+	}
+
+	var updates []refUpdate
+	switch {
+	case args[0] == "pre-receive" && len(positional) == 0:
+		if updates, err = readRefUpdates(stdio.In); err != nil {
+			return failure("Error reading ref updates: %v", err)
+		}
+	case args[0] == "update" && len(positional) == 3:
+		updates = []refUpdate{{Ref: positional[0], Old: positional[1], New: positional[2]}}
+	default:
+		return usageError(serverHookUsage)
+	}
+	if *configFile != "" && *configRev != "" {
+		return usageError("Use either --config or --config-rev, not both")
+	}
+
+	ctx := context.Background()
+	var fixed *ruleSource
+	switch {
+	case *configFile != "":
+		cfg, err := readConfigFile(*configFile)
+		if err != nil {
+			return configError("Error loading %s: %v", *configFile, err)
+		}
+		fixed = &ruleSource{cfg: cfg}
+	case *configRev != "":
+		cfg, scopes, err := loadConfigAt(ctx, *configRev)
+		if err != nil {
+			return configError("Error loading config from %s: %v", *configRev, err)
+		}
+		fixed = &ruleSource{cfg: cfg, scopes: scopes}
+	}
+
+	seen := make(map[string]bool)
+	rejected := 0
+	for _, u := range updates {
+		ok, err := checkRefUpdate(ctx, stdio.Err, fixed, u, seen)
+		if err != nil {
+			return failure("Error checking %s: %v", u.Ref, err)
+		}
+		if !ok {
+			rejected++
+			fmt.Fprintf(stdio.Err, "jitt: rejected %s\n", u.Ref)
+		}
+	}
+
+	if rejected > 0 {
+		return failure("jitt: push rejected: fix the commits above (git rebase -i) and push again")
+	}
+	return nil
+}
+
+// readRefUpdates parses the "<old> <new> <ref>" lines git feeds pre-receive
+func readRefUpdates(r io.Reader) ([]refUpdate, error) {
+	var updates []refUpdate
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		updates = append(updates, refUpdate{Old: fields[0], New: fields[1], Ref: fields[2]})
+	}
+	return updates, scanner.Err()
+}
+
+// checkRefUpdate checks the commits u adds to a branch that no other ref
+// already has, skipping those in seen, and the branch name when u creates it.
+// It prints what it found to w and reports whether the update may go ahead.
+// Without fixed rules the .jitt.yaml files in the pushed tree apply, see
+// pushedRules.
+func checkRefUpdate(ctx context.Context, w io.Writer, fixed *ruleSource, u refUpdate, seen map[string]bool) (bool, error) {
+	branch, isBranch := strings.CutPrefix(u.Ref, "refs/heads/")
+	if !isBranch || gitrepo.IsZeroSHA(u.New) {
+		return true, nil
+	}
+
+	var rules ruleSource
+	if fixed != nil {
+		rules = *fixed
+	} else {
+		var ok bool
+		if rules, ok = pushedRules(ctx, w, u); !ok {
+			return false, nil
+		}
+	}
+
+	kind := "update"
+	switch {
+	case gitrepo.IsZeroSHA(u.Old):
+		kind = "new branch"
+	default:
+		fastForward, err := repo.IsAncestor(ctx, u.Old, u.New)
+		if err != nil {
+			return false, err
+		}
+		if !fastForward {
+			kind = "forced update"
+		}
+	}
+
+	// Commits reachable from a ref the server already has were checked when
+	// they arrived; this also leaves out what a force push merely rewinds to
+	results, err := checkCommits(ctx, w, rules.cfg, rules.scopes, branch, u.New, "--not", "--all")
+	if err != nil {
+		return false, err
+	}
+	fresh := results[:0]
+	for _, r := range results {
+		if !seen[r.SHA] {
+			seen[r.SHA] = true
+			fresh = append(fresh, r)
+		}
+	}
+	results = fresh
+	if kind == "new branch" {
+		results = append(results, checkResult{Name: "branch " + branch, Problems: lib.CheckBranch(rules.cfg, branch)})
+	}
+
+	fmt.Fprintf(w, "jitt: checking %s (%s)\n", u.Ref, kind)
+	writeTextReport(w, results)
+	errs, _ := countProblems(results)
+	return errs == 0, nil
+}
+
+// pushedRules loads the .jitt.yaml files u is checked against: those of the
+// pushed tree or, when it has none, those of the tip it replaces or of the
+// repository's default branch, so a push can't switch the checks off by
+// deleting its config. It reports why to w when there are none to use.
+func pushedRules(ctx context.Context, w io.Writer, u refUpdate) (ruleSource, bool) {
+	candidates := []string{u.New}
+	if !gitrepo.IsZeroSHA(u.Old) {
+		candidates = append(candidates, u.Old)
+	}
+	if head, err := repo.Run(ctx, "symbolic-ref", "-q", "HEAD"); err == nil {
+		if _, err := repo.ResolveCommit(ctx, head); err == nil {
+			candidates = append(candidates, head)
+		}
+	}
+
+	for _, rev := range candidates {
+		cfg, scopes, err := loadConfigAt(ctx, rev)
+		switch {
+		case errors.Is(err, config.ErrNotFound):
+			continue
+		case err != nil:
+			fmt.Fprintf(w, "jitt: %s has an invalid .jitt.yaml: %v\n", u.Ref, err)
+			return ruleSource{}, false
+		}
+		if rev != u.New {
+			fmt.Fprintf(w, "jitt: %s has no .jitt.yaml, checking it against the one in %s\n", u.Ref, describeRev(rev, u))
+		}
+		return ruleSource{cfg: cfg, scopes: scopes}, true
+	}
+
+	fmt.Fprintf(w, "jitt: no .jitt.yaml in %s, its previous tip or the default branch to check it against\n", u.Ref)
+	return ruleSource{}, false
+}
+
+// describeRev names a revision pushedRules took the config from
+func describeRev(rev string, u refUpdate) string {
+	if rev == u.Old {
+		return "its previous tip " + rev[:7]
+	}
+	return strings.TrimPrefix(rev, "refs/heads/")
+}
+
+// readConfigFile reads a .jitt.yaml kept outside any repository
+func readConfigFile(path string) (*config.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return config.Parse(f)
+}
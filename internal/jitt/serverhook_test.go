@@ -0,0 +1,259 @@
+package jitt
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/bbommarito/jitt/internal/git/gittest"
+)
+
+var _ = Describe("readRefUpdates", func() {
+	It("should parse the lines git feeds pre-receive, skipping malformed ones", func() {
+		updates, err := readRefUpdates(strings.NewReader(
+			"aaa bbb refs/heads/main\n\nnot a ref update line\n000 ccc refs/heads/feature/ABC-1\n"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(updates).To(Equal([]refUpdate{
+			{Old: "aaa", New: "bbb", Ref: "refs/heads/main"},
+			{Old: "000", New: "ccc", Ref: "refs/heads/feature/ABC-1"},
+		}))
+	})
+})
+
+var _ = Describe("jitt server-hook command", func() {
+	const rules = "jira:\n  project: ABC\nbranch:\n  pattern: ^(feature|bugfix)/ABC-[0-9]+\n"
+
+	var (
+		local  *gittest.Repo
+		server *gittest.Repo
+	)
+
+	// installServerHook makes the server run jitt from one of its hooks
+	installServerHook := func(hook string, args ...string) {
+		script := fmt.Sprintf("#!/bin/sh\nexec %s server-hook %s %s \"$@\"\n", pathToJittBinary, hook, strings.Join(args, " "))
+		//nolint:gosec // hooks must be executable
+		Expect(os.WriteFile(filepath.Join(server.Dir, "hooks", hook), []byte(script), 0o755)).To(Succeed())
+	}
+
+	push := func(args ...string) *gexec.Session {
+		command := exec.Command("git", append([]string{"push", "origin"}, args...)...)
+		command.Dir = local.Dir
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(session).Should(gexec.Exit())
+		return session
+	}
+
+	serverHas := func(ref string) string {
+		out, err := exec.Command("git", "-C", server.Dir, "rev-parse", "--verify", "--quiet", ref).Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(out))
+	}
+
+	BeforeEach(func() {
+		local = gittest.New(GinkgoT())
+		local.File(".jitt.yaml", rules).Commit("ABC-1 Add jitt config")
+		server = local.CloneBare()
+		local.Git("remote", "add", "origin", server.Dir)
+		installServerHook("pre-receive")
+	})
+
+	It("should accept commits that reference tickets", func() {
+		sha := local.Commit("ABC-2 Add feature")
+
+		session := push("main")
+
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("remote: jitt: checking refs/heads/main (update)"))
+		Expect(serverHas("main")).To(Equal(sha))
+	})
+
+	It("should reject a push with a commit missing a ticket, in git's remote messages", func() {
+		before := serverHas("main")
+		local.Commit("ABC-2 Add feature")
+		local.Commit("Fix typo")
+
+		session := push("main")
+
+		Expect(session.ExitCode()).NotTo(Equal(0))
+		output := string(session.Err.Contents())
+		Expect(output).To(ContainSubstring("remote: jitt: checking refs/heads/main (update)"))
+		Expect(output).To(ContainSubstring("Fix typo"))
+		Expect(output).To(ContainSubstring("remote: Checked 2 items: 1 errors"))
+		Expect(output).To(ContainSubstring("remote: jitt: rejected refs/heads/main"))
+		Expect(output).To(ContainSubstring("remote: jitt: push rejected"))
+		Expect(output).To(ContainSubstring("pre-receive hook declined"))
+		Expect(serverHas("main")).To(Equal(before))
+	})
+
+	It("should check only the new commits and the name of a new branch", func() {
+		local.Branch("wip").Commit("ABC-3 Start work")
+
+		session := push("wip")
+
+		Expect(session.ExitCode()).NotTo(Equal(0))
+		output := string(session.Err.Contents())
+		Expect(output).To(ContainSubstring("checking refs/heads/wip (new branch)"))
+		Expect(output).To(ContainSubstring(`branch "wip" does not match branch.pattern`))
+		Expect(output).To(ContainSubstring("Checked 2 items: 1 errors"))
+
+		local.Git("branch", "-m", "feature/ABC-3")
+		session = push("feature/ABC-3")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("Checked 2 items: 0 errors"))
+	})
+
+	It("should check a force push without rechecking commits the server already has", func() {
+		local.Commit("ABC-2 First try")
+		Expect(push("main").ExitCode()).To(Equal(0))
+		local.Git("reset", "-q", "--hard", "HEAD^")
+		sha := local.Commit("ABC-2 Second try")
+
+		session := push("--force", "main")
+
+		Expect(session.ExitCode()).To(Equal(0))
+		output := string(session.Err.Contents())
+		Expect(output).To(ContainSubstring("checking refs/heads/main (forced update)"))
+		Expect(output).To(ContainSubstring("Checked 1 items: 0 errors"))
+		Expect(serverHas("main")).To(Equal(sha))
+	})
+
+	It("should accept recorded bypasses unless the branch disables them", func() {
+		local.Commit("Stop the bleeding\n\nJitt-Skip: prod is down")
+
+		session := push("main")
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("checks skipped: prod is down"))
+
+		local.File(".jitt.yaml", rules+"bypass:\n  disabled_branches: [main]\n").Commit("ABC-8 Lock down main")
+		local.Commit("Another shortcut [skip-jitt: still down]")
+		session = push("main")
+		Expect(session.ExitCode()).NotTo(Equal(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("skipping jitt's checks is not allowed on branch main"))
+	})
+
+	It("should apply validate.exempt like local hooks do", func() {
+		local.File(".jitt.yaml", rules+"validate:\n  exempt:\n    paths: [\"*.md\"]\n    merges: true\n").
+			Commit("ABC-9 Exempt docs and merges")
+		local.Branch("feature/ABC-10").File("feature.go", "package main\n").Commit("ABC-10 Add feature")
+		local.Checkout("main").File("README.md", "# Docs\n").Commit("Fix typo in README")
+		local.Git("merge", "--no-ff", "-q", "-m", "Merge branch 'feature/ABC-10'", "feature/ABC-10")
+
+		session := push("main")
+
+		Expect(session.ExitCode()).To(Equal(0))
+		output := string(session.Err.Contents())
+		Expect(output).To(ContainSubstring("exempt: only touches exempt paths"))
+		Expect(output).To(ContainSubstring("exempt: merge commit"))
+	})
+
+	It("should let branches be deleted", func() {
+		local.Branch("feature/ABC-4").Commit("ABC-4 Work")
+		Expect(push("feature/ABC-4").ExitCode()).To(Equal(0))
+
+		session := push("--delete", "feature/ABC-4")
+
+		Expect(session.ExitCode()).To(Equal(0))
+		Expect(serverHas("refs/heads/feature/ABC-4")).To(BeEmpty())
+	})
+
+	It("should apply the .jitt.yaml of the pushed tree", func() {
+		local.File(".jitt.yaml", "jira:\n  project: XYZ\n").Commit("XYZ-1 Move to XYZ")
+
+		session := push("main")
+
+		Expect(session.ExitCode()).To(Equal(0))
+	})
+
+	It("should enforce a server-side config the pushed tree cannot loosen", func() {
+		serverConfig := filepath.Join(GinkgoT().TempDir(), "jitt.yaml")
+		Expect(os.WriteFile(serverConfig, []byte(rules), 0o600)).To(Succeed())
+		installServerHook("pre-receive", "--config", serverConfig)
+		local.Remove(".jitt.yaml").Commit("Drop the ticket rules")
+
+		session := push("main")
+
+		Expect(session.ExitCode()).NotTo(Equal(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("rejected refs/heads/main"))
+	})
+
+	It("should enforce the rules committed on the server's main branch", func() {
+		installServerHook("pre-receive", "--config-rev", "refs/heads/main")
+		local.Branch("feature/ABC-7")
+		local.File(".jitt.yaml", "jira:\n  project: XYZ\n").Commit("XYZ-1 Relax the rules")
+
+		session := push("feature/ABC-7")
+
+		Expect(session.ExitCode()).NotTo(Equal(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("rejected refs/heads/feature/ABC-7"))
+	})
+
+	It("should check a push deleting .jitt.yaml against the tip it replaces", func() {
+		before := serverHas("main")
+		local.Remove(".jitt.yaml").Commit("Drop the ticket rules")
+
+		session := push("main")
+
+		Expect(session.ExitCode()).NotTo(Equal(0))
+		output := string(session.Err.Contents())
+		Expect(output).To(ContainSubstring("refs/heads/main has no .jitt.yaml, checking it against the one in its previous tip " + before[:7]))
+		Expect(output).To(ContainSubstring("rejected refs/heads/main"))
+	})
+
+	It("should check a new branch without .jitt.yaml against the default branch", func() {
+		local.Branch("feature/ABC-11").Remove(".jitt.yaml").Commit("Drop the ticket rules")
+
+		session := push("feature/ABC-11")
+
+		Expect(session.ExitCode()).NotTo(Equal(0))
+		output := string(session.Err.Contents())
+		Expect(output).To(ContainSubstring("refs/heads/feature/ABC-11 has no .jitt.yaml, checking it against the one in main"))
+		Expect(output).To(ContainSubstring("rejected refs/heads/feature/ABC-11"))
+	})
+
+	It("should reject pushes when no .jitt.yaml can be found", func() {
+		local = gittest.New(GinkgoT())
+		local.File("README.md", "# no jitt here\n").Commit("Initial commit")
+		server = local.CloneBare()
+		local.Git("remote", "add", "origin", server.Dir)
+		installServerHook("pre-receive")
+		local.Commit("ABC-12 Still no config")
+
+		session := push("main")
+
+		Expect(session.ExitCode()).NotTo(Equal(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring(
+			"no .jitt.yaml in refs/heads/main, its previous tip or the default branch to check it against"))
+	})
+
+	It("should reject only the offending refs from the update hook", func() {
+		Expect(os.Remove(filepath.Join(server.Dir, "hooks", "pre-receive"))).To(Succeed())
+		installServerHook("update")
+		good := local.Branch("feature/ABC-5").Commit("ABC-5 Good work")
+		local.Checkout("main").Branch("feature/ABC-6").Commit("Untracked work")
+
+		session := push("feature/ABC-5", "feature/ABC-6")
+
+		Expect(session.ExitCode()).NotTo(Equal(0))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("hook declined to update refs/heads/feature/ABC-6"))
+		Expect(serverHas("refs/heads/feature/ABC-5")).To(Equal(good))
+		Expect(serverHas("refs/heads/feature/ABC-6")).To(BeEmpty())
+	})
+
+	It("should print usage for unknown hooks", func() {
+		session, err := gexec.Start(exec.Command(pathToJittBinary, "server-hook", "post-receive"), GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(ExitUsage))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("Usage: jitt server-hook pre-receive"))
+	})
+})
@@ -0,0 +1,230 @@
+package jitt
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gitrepo "github.com/bbommarito/jitt/internal/git"
+	"github.com/bbommarito/jitt/internal/jira"
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// executeSmartCommands applies the commands through the Jira API, reporting
+// but not stopping on failures
+func executeSmartCommands(ctx context.Context, stdio IO, client *jira.Client, dryRun bool, commands []lib.SmartCommand) {
+	for _, c := range commands {
+		for _, key := range c.Keys {
+			if err := executeSmartCommand(ctx, stdio, client, dryRun, key, c); err != nil {
+				fmt.Fprintf(stdio.Err, "jitt: #%s on %s failed: %v\n", c.Name, key, err)
+			}
+		}
+	}
+}
+
+func executeSmartCommand(ctx context.Context, stdio IO, client *jira.Client, dryRun bool, key string, c lib.SmartCommand) error {
+	if err := c.Check(); err != nil {
+		return err
+	}
+
+	switch c.Name {
+	case "comment":
+		if dryRun {
+			fmt.Fprintf(stdio.Out, "[dry-run] would comment on %s: %s\n", key, c.Args)
+			return nil
+		}
+		if err := client.AddComment(ctx, key, c.Args); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdio.Out, "Commented on %s\n", key)
+	case "time":
+		duration, comment, _ := lib.SplitWorkTime(c.Args)
+		if dryRun {
+			fmt.Fprintf(stdio.Out, "[dry-run] would log %s on %s\n", duration, key)
+			return nil
+		}
+		if err := client.AddWorklog(ctx, key, jira.Worklog{TimeSpent: duration, Comment: comment}); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdio.Out, "Logged %s on %s\n", duration, key)
+	default:
+		return transitionIssue(ctx, stdio.Out, client, key, c.TransitionName(), dryRun)
+	}
+	return nil
+}
+
+// smartCommitsLog and smartCommitsQueue are kept in the git directory: the
+// commits whose smart commits have run, and the pushed commits waiting for
+// the remote to confirm them as "<sha> <remote>" lines, oldest first
+const (
+	smartCommitsLog   = "jitt/smart-commits"
+	smartCommitsQueue = "jitt/smart-commits-pending"
+)
+
+// queueSmartCommits remembers the commits a push to remote is sending, so
+// their smart commits run once the remote has accepted them rather than
+// while the push can still be rejected
+func queueSmartCommits(stdio IO, cfg *config.Config, remote string, commits []gitrepo.Commit) {
+	if cfg.SmartCommits.Mode != config.SmartCommitsExecute || len(commits) == 0 {
+		return
+	}
+
+	logPath, queuePath, err := smartCommitPaths()
+	if err != nil {
+		fmt.Fprintf(stdio.Err, "jitt: skipping smart commits: %v\n", err)
+		return
+	}
+	processed := readLines(logPath)
+	queued := make(map[string]bool)
+	for _, line := range readLineList(queuePath) {
+		sha, _, _ := strings.Cut(line, " ")
+		queued[sha] = true
+	}
+
+	var lines []string
+	for _, c := range commits {
+		if !processed[c.SHA] && !queued[c.SHA] {
+			lines = append(lines, c.SHA+" "+remote)
+		}
+	}
+	if len(lines) > 0 {
+		if err := appendLines(queuePath, lines); err != nil {
+			fmt.Fprintf(stdio.Err, "jitt: could not queue smart commits: %v\n", err)
+		}
+	}
+}
+
+// runConfirmedSmartCommits executes the smart commits of queued commits that
+// a remote-tracking ref of the remote they were pushed to now contains, which
+// git updates once a push has been accepted. Commits not confirmed yet stay
+// queued. Processed commits are remembered so a commit pushed again, or to
+// another remote, does not post the same comment or worklog twice.
+func runConfirmedSmartCommits(stdio IO, cfg *config.Config) {
+	if cfg.SmartCommits.Mode != config.SmartCommitsExecute {
+		return
+	}
+	logPath, queuePath, err := smartCommitPaths()
+	if err != nil {
+		return
+	}
+	queue := readLineList(queuePath)
+	if len(queue) == 0 {
+		return
+	}
+	processed := readLines(logPath)
+
+	var confirmed, pending []string
+	for _, line := range queue {
+		sha, remote, _ := strings.Cut(line, " ")
+		switch {
+		case processed[sha]:
+		case onRemote(sha, remote):
+			confirmed = append(confirmed, sha)
+		default:
+			pending = append(pending, line)
+		}
+	}
+	if len(confirmed) == 0 {
+		return
+	}
+
+	client, err := newJiraClient(cfg)
+	if err != nil {
+		fmt.Fprintf(stdio.Err, "jitt: skipping smart commits: %v\n", err)
+		return
+	}
+	for _, sha := range confirmed {
+		commits, err := commitsInRange("-1", sha)
+		if err != nil || len(commits) == 0 {
+			continue
+		}
+		executeSmartCommands(context.Background(), stdio, client, cfg.Workflow.DryRun, lib.ParseSmartCommands(cfg, commits[0].Message))
+	}
+
+	if !cfg.Workflow.DryRun {
+		if err := appendLines(logPath, confirmed); err != nil {
+			fmt.Fprintf(stdio.Err, "jitt: could not record processed smart commits: %v\n", err)
+		}
+	}
+	if err := writeLines(queuePath, pending); err != nil {
+		fmt.Fprintf(stdio.Err, "jitt: could not update queued smart commits: %v\n", err)
+	}
+}
+
+// onRemote reports whether a remote-tracking ref of remote (of any remote,
+// when pushing to a URL) contains the commit
+func onRemote(sha, remote string) bool {
+	pattern := "refs/remotes/"
+	if remote != "" && !strings.Contains(remote, "/") {
+		pattern += remote + "/"
+	}
+	out, err := runGit("for-each-ref", "--count=1", "--format=%(refname)", "--contains", sha, pattern)
+	return err == nil && out != ""
+}
+
+func smartCommitPaths() (logPath, queuePath string, err error) {
+	if logPath, err = runGit("rev-parse", "--git-path", smartCommitsLog); err != nil {
+		return "", "", err
+	}
+	queuePath, err = runGit("rev-parse", "--git-path", smartCommitsQueue)
+	return logPath, queuePath, err
+}
+
+// readLines returns the set of lines in a file, or an empty set if it can't be read
+func readLines(path string) map[string]bool {
+	lines := make(map[string]bool)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return lines
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			lines[line] = true
+		}
+	}
+	return lines
+}
+
+// readLineList returns the lines in a file in order, or nothing if it can't be read
+func readLineList(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// writeLines replaces a file with lines, removing it when there are none
+func writeLines(path string, lines []string) error {
+	if len(lines) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+}
+
+func appendLines(path string, lines []string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,154 @@
+package jitt
+
+import (
+	"os"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("smart commit execution", func() {
+	var (
+		tmpDir string
+		oldCwd string
+		fake   *fakeJira
+		zero   = strings.Repeat("0", 40)
+	)
+
+	// pushBranch simulates pushing main to a remote that doesn't have it yet
+	pushBranch := func() *gexec.Session {
+		sha := git("rev-parse", "HEAD")
+		return runHookCommand("refs/heads/main "+sha+" refs/heads/main "+zero+"\n", "pre-push", "origin", "url")
+	}
+
+	// acceptPush simulates the remote accepting the push: git updates the
+	// remote-tracking ref, running the reference-transaction hook
+	acceptPush := func() *gexec.Session {
+		sha := git("rev-parse", "HEAD")
+		git("update-ref", "refs/remotes/origin/main", sha)
+		return runHookCommand(zero+" "+sha+" refs/remotes/origin/main\n", "reference-transaction", "committed")
+	}
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		initGitRepo()
+		fake = newFakeJira()
+		fake.addIssue("ABC-123", "In Progress")
+	})
+
+	AfterEach(func() {
+		fake.Close()
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	Context("in execute mode", func() {
+		BeforeEach(func() {
+			Expect(fake.writeConfig("smart_commits:\n  mode: execute\n")).To(Succeed())
+		})
+
+		It("should apply comments, worklogs and transitions once the remote accepts the commits", func() {
+			commit("ABC-123 #comment fixed null check #time 1h 30m #resolve")
+
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			Expect(fake.Comments()).To(BeEmpty())
+
+			Eventually(acceptPush()).Should(gexec.Exit(0))
+			Expect(fake.Comments()).To(ConsistOf("ABC-123: fixed null check"))
+			Expect(fake.Worklogs()).To(HaveLen(1))
+			Expect(fake.Worklogs()[0]).To(HaveKeyWithValue("timeSpent", "1h 30m"))
+			Expect(fake.Transitioned()).To(ConsistOf("ABC-123 -> Done"))
+		})
+
+		It("should only process commits the remote doesn't have", func() {
+			commit("ABC-123 #comment first")
+			old := git("rev-parse", "HEAD")
+			commit("ABC-123 #comment second")
+			sha := git("rev-parse", "HEAD")
+
+			session := runHookCommand("refs/heads/main "+sha+" refs/heads/main "+old+"\n", "pre-push", "origin", "url")
+			Eventually(session).Should(gexec.Exit(0))
+			Eventually(acceptPush()).Should(gexec.Exit(0))
+			Expect(fake.Comments()).To(ConsistOf("ABC-123: second"))
+		})
+
+		It("should not apply the same commit twice when a push is retried", func() {
+			commit("ABC-123 #comment once")
+
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			Eventually(acceptPush()).Should(gexec.Exit(0))
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			Eventually(acceptPush()).Should(gexec.Exit(0))
+			Expect(fake.Comments()).To(HaveLen(1))
+		})
+
+		It("should not touch Jira for a rejected push", func() {
+			commit("ABC-123 #comment rejected")
+
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			// Ref updates other than remote-tracking ones don't confirm anything
+			Eventually(runHookCommand(zero+" "+git("rev-parse", "HEAD")+" refs/heads/main\n",
+				"reference-transaction", "committed")).Should(gexec.Exit(0))
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			Expect(fake.Comments()).To(BeEmpty())
+		})
+
+		It("should catch up on the next push when the remote-tracking ref moved unnoticed", func() {
+			commit("ABC-123 #comment first")
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			git("update-ref", "refs/remotes/origin/main", "HEAD")
+
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			Expect(fake.Comments()).To(ConsistOf("ABC-123: first"))
+		})
+
+		It("should warn about failures without blocking anything", func() {
+			commit("ABC-404 #comment nobody home")
+
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			session := acceptPush()
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("#comment on ABC-404 failed"))
+		})
+	})
+
+	Context("in execute mode with dry_run", func() {
+		BeforeEach(func() {
+			Expect(fake.writeConfig("smart_commits:\n  mode: execute\nworkflow:\n  dry_run: true\n")).To(Succeed())
+		})
+
+		It("should only report what would happen", func() {
+			commit("ABC-123 #comment fixed #time 2h")
+
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			session := acceptPush()
+			Eventually(session).Should(gexec.Exit(0))
+			output := string(session.Out.Contents())
+			Expect(output).To(ContainSubstring("[dry-run] would comment on ABC-123: fixed"))
+			Expect(output).To(ContainSubstring("[dry-run] would log 2h on ABC-123"))
+			Expect(fake.Comments()).To(BeEmpty())
+		})
+	})
+
+	Context("in the default validate mode", func() {
+		BeforeEach(func() {
+			Expect(fake.writeConfig("")).To(Succeed())
+		})
+
+		It("should not touch Jira on push", func() {
+			commit("ABC-123 #comment fixed")
+
+			Eventually(pushBranch()).Should(gexec.Exit(0))
+			Eventually(acceptPush()).Should(gexec.Exit(0))
+			Expect(fake.Comments()).To(BeEmpty())
+		})
+	})
+})
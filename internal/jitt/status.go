@@ -0,0 +1,227 @@
+package jitt
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// statusReport is everything 'jitt status' reports, in its JSON shape
+type statusReport struct {
+	Branch    string          `json:"branch"`
+	Detached  bool            `json:"detached"`
+	Ticket    *ticketStatus   `json:"ticket"`
+	Upstream  string          `json:"upstream,omitempty"`
+	Ahead     int             `json:"ahead"`
+	Behind    int             `json:"behind"`
+	Base      string          `json:"base,omitempty"`
+	Commits   int             `json:"commits"`
+	Untracked int             `json:"commits_without_ticket"`
+	Hooks     map[string]bool `json:"hooks"`
+	Config    map[string]any  `json:"config"`
+}
+
+// ticketStatus describes the ticket detected in the branch name
+type ticketStatus struct {
+	Key      string `json:"key"`
+	Summary  string `json:"summary,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Assignee string `json:"assignee,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// HandleStatus handles the 'jitt status' command
+func HandleStatus(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	output := fs.String("output", "text", "output format: text or json")
+	if _, err := parseFlags(fs, args); err != nil {
+		return flagError(err)
+	}
+	if *output != "text" && *output != "json" {
+		return usageError("Unknown output format: %s (expected text or json)", *output)
+	}
+
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+
+	report := buildStatus(context.Background(), cfg)
+	if *output == "json" {
+		enc := json.NewEncoder(stdio.Out)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+		return nil
+	}
+	printStatus(stdio.Out, report)
+	return nil
+}
+
+func buildStatus(ctx context.Context, cfg *config.Config) *statusReport {
+	report := &statusReport{Hooks: installedHooks()}
+	// The config already loaded, so reading its settings again can't fail
+	report.Config, _ = config.Settings()
+
+	branch, err := currentBranch()
+	if err != nil {
+		report.Branch = "(detached HEAD)"
+		report.Detached = true
+	} else {
+		report.Branch = branch
+		if keys := lib.BranchKeys(cfg, branch); len(keys) > 0 {
+			report.Ticket = lookupTicket(ctx, cfg, keys[0])
+		}
+	}
+
+	if upstream, err := runGit("rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{upstream}"); err == nil {
+		report.Upstream = upstream
+		if counts, err := runGit("rev-list", "--left-right", "--count", "@{upstream}...HEAD"); err == nil {
+			if fields := strings.Fields(counts); len(fields) == 2 {
+				report.Behind, _ = strconv.Atoi(fields[0])
+				report.Ahead, _ = strconv.Atoi(fields[1])
+			}
+		}
+	}
+
+	report.Base = statusBase(cfg, report)
+	if report.Base != "" {
+		if messages, err := commitMessages(report.Base + "..HEAD"); err == nil {
+			report.Commits = len(messages)
+			for _, msg := range messages {
+				// Count the way validate does: in trailer mode only trailers reference tickets
+				if len(lib.ReferencedKeys(cfg, msg)) == 0 {
+					report.Untracked++
+				}
+			}
+		}
+	}
+	return report
+}
+
+// statusBase picks what the branch's own commits are counted against: its
+// upstream, or the main branch when there is no upstream yet
+func statusBase(cfg *config.Config, report *statusReport) string {
+	if report.Upstream != "" {
+		return report.Upstream
+	}
+	if report.Detached || report.Branch == cfg.Workflow.MainBranch {
+		return ""
+	}
+	if _, err := runGit("rev-parse", "--verify", "-q", cfg.Workflow.MainBranch); err != nil {
+		return ""
+	}
+	return cfg.Workflow.MainBranch
+}
+
+// lookupTicket fetches ticket details from Jira when it is configured
+func lookupTicket(ctx context.Context, cfg *config.Config, key string) *ticketStatus {
+	ticket := &ticketStatus{Key: key}
+	client, err := newJiraClient(cfg)
+	if err != nil {
+		return ticket
+	}
+
+	ticket.URL = client.BrowseURL(key)
+	issue, err := client.Issue(ctx, key)
+	if err != nil {
+		ticket.Error = err.Error()
+		return ticket
+	}
+	ticket.Summary = issue.Fields.Summary
+	ticket.Status = issue.Fields.Status.Name
+	if issue.Fields.Assignee != nil {
+		ticket.Assignee = issue.Fields.Assignee.DisplayName
+	}
+	return ticket
+}
+
+// installedHooks reports, for each hook jitt manages, whether jitt's script is installed
+func installedHooks() map[string]bool {
+	hooks := make(map[string]bool, len(managedHooks))
+	dir, err := hooksDir()
+	for _, name := range managedHooks {
+		if err != nil {
+			hooks[name] = false
+			continue
+		}
+		content, readErr := os.ReadFile(filepath.Join(dir, name))
+		hooks[name] = readErr == nil && strings.Contains(string(content), hookMarker)
+	}
+	return hooks
+}
+
+func printStatus(w io.Writer, report *statusReport) {
+	branch := report.Branch
+	if report.Upstream != "" {
+		branch += fmt.Sprintf(" (tracking %s: %d ahead, %d behind)", report.Upstream, report.Ahead, report.Behind)
+	} else if !report.Detached {
+		branch += " (no upstream)"
+	}
+	fmt.Fprintf(w, "Branch:  %s\n", branch)
+
+	switch t := report.Ticket; {
+	case t == nil:
+		fmt.Fprintln(w, "Ticket:  none detected in branch name")
+	case t.Summary != "":
+		fmt.Fprintf(w, "Ticket:  %s — %s\n", t.Key, t.Summary)
+		assignee := t.Assignee
+		if assignee == "" {
+			assignee = "unassigned"
+		}
+		fmt.Fprintf(w, "         Status: %s · Assignee: %s\n", t.Status, assignee)
+		fmt.Fprintf(w, "         %s\n", t.URL)
+	case t.Error != "":
+		fmt.Fprintf(w, "Ticket:  %s (could not fetch details: %s)\n", t.Key, t.Error)
+	default:
+		fmt.Fprintf(w, "Ticket:  %s\n", t.Key)
+	}
+
+	if report.Base != "" {
+		fmt.Fprintf(w, "Commits: %d of %d since %s lack a ticket key\n", report.Untracked, report.Commits, report.Base)
+	}
+
+	var hooks []string
+	for _, name := range managedHooks {
+		mark := "❌"
+		if report.Hooks[name] {
+			mark = "✅"
+		}
+		hooks = append(hooks, mark+" "+name)
+	}
+	fmt.Fprintf(w, "Hooks:   %s\n", strings.Join(hooks, "  "))
+
+	fmt.Fprintln(w, "Config:")
+	for _, line := range flattenSettings("", report.Config) {
+		fmt.Fprintf(w, "  %s\n", line)
+	}
+}
+
+// flattenSettings renders nested settings as sorted "a.b = value" lines
+func flattenSettings(prefix string, settings map[string]any) []string {
+	var lines []string
+	for key, value := range settings {
+		name := key
+		if prefix != "" {
+			name = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			lines = append(lines, flattenSettings(name, nested)...)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s = %v", name, value))
+	}
+	sort.Strings(lines)
+	return lines
+}
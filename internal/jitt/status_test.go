@@ -0,0 +1,158 @@
+package jitt
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+func runStatusCommand(args ...string) *gexec.Session {
+	command := exec.Command(pathToJittBinary, append([]string{"status"}, args...)...)
+	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+	return session
+}
+
+var _ = Describe("jitt status command", func() {
+	var (
+		tmpDir string
+		oldCwd string
+		fake   *fakeJira
+	)
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		initGitRepo()
+		fake = newFakeJira()
+		fake.addIssue("ABC-1", "In Progress")
+		fake.assign("ABC-1", "Sam Doe")
+		Expect(fake.writeConfig("")).To(Succeed())
+		commit("ABC-1 initial commit")
+	})
+
+	AfterEach(func() {
+		fake.Close()
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should reject unknown output formats", func() {
+		session := runStatusCommand("--output", "xml")
+
+		Eventually(session).Should(gexec.Exit(ExitUsage))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("Unknown output format: xml"))
+	})
+
+	Context("on a feature branch without upstream", func() {
+		BeforeEach(func() {
+			git("checkout", "-q", "-b", "feature/ABC-1-login")
+			commit("ABC-1 add form")
+			commit("tweak styles")
+		})
+
+		It("should show the branch, ticket details and untracked commits", func() {
+			session := runStatusCommand()
+
+			Eventually(session).Should(gexec.Exit(0))
+			output := string(session.Out.Contents())
+			Expect(output).To(ContainSubstring("Branch:  feature/ABC-1-login (no upstream)"))
+			Expect(output).To(ContainSubstring("Ticket:  ABC-1 — Summary of ABC-1"))
+			Expect(output).To(ContainSubstring("Status: In Progress · Assignee: Sam Doe"))
+			Expect(output).To(ContainSubstring(fake.server.URL + "/browse/ABC-1"))
+			Expect(output).To(ContainSubstring("Commits: 1 of 2 since main lack a ticket key"))
+			Expect(output).To(ContainSubstring("❌ pre-push"))
+			Expect(output).To(ContainSubstring("jira.project = ABC"))
+			Expect(output).To(ContainSubstring("workflow.main_branch = main"))
+		})
+
+		It("should count the way validate does in trailer mode", func() {
+			Expect(fake.writeConfig("commit:\n  position: trailer\n")).To(Succeed())
+			git("checkout", "-q", "-b", "feature/ABC-1-trailers", "main")
+			commit("Add form\n\nRefs: ABC-1")
+			commit("ABC-1 key only in the subject")
+
+			session := runStatusCommand()
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("Commits: 1 of 2 since main lack a ticket key"))
+		})
+
+		It("should show installed hooks", func() {
+			Eventually(runHookCommand("", "install")).Should(gexec.Exit(0))
+
+			session := runStatusCommand()
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("✅ pre-push"))
+		})
+
+		It("should emit JSON", func() {
+			session := runStatusCommand("--output", "json")
+			Eventually(session).Should(gexec.Exit(0))
+
+			var report map[string]any
+			Expect(json.Unmarshal(session.Out.Contents(), &report)).To(Succeed())
+			Expect(report).To(HaveKeyWithValue("branch", "feature/ABC-1-login"))
+			Expect(report).To(HaveKeyWithValue("commits", BeNumerically("==", 2)))
+			Expect(report).To(HaveKeyWithValue("commits_without_ticket", BeNumerically("==", 1)))
+			Expect(report["ticket"]).To(HaveKeyWithValue("status", "In Progress"))
+			Expect(report["ticket"]).To(HaveKeyWithValue("assignee", "Sam Doe"))
+			Expect(report["hooks"]).To(HaveKeyWithValue("commit-msg", false))
+			Expect(report["config"]).To(HaveKeyWithValue("jira", HaveKeyWithValue("project", "ABC")))
+		})
+	})
+
+	Context("tracking an upstream branch", func() {
+		BeforeEach(func() {
+			remote := GinkgoT().TempDir()
+			git("init", "-q", "--bare", remote)
+			git("remote", "add", "origin", remote)
+			git("checkout", "-q", "-b", "ABC-1-sync")
+			git("push", "-q", "-u", "origin", "ABC-1-sync")
+			commit("ABC-1 local only")
+			commit("no key")
+		})
+
+		It("should show ahead/behind counts against the upstream", func() {
+			session := runStatusCommand()
+
+			Eventually(session).Should(gexec.Exit(0))
+			output := string(session.Out.Contents())
+			Expect(output).To(ContainSubstring("ABC-1-sync (tracking origin/ABC-1-sync: 2 ahead, 0 behind)"))
+			Expect(output).To(ContainSubstring("Commits: 1 of 2 since origin/ABC-1-sync lack a ticket key"))
+		})
+	})
+
+	Context("on a branch without a ticket", func() {
+		It("should say so", func() {
+			session := runStatusCommand()
+
+			Eventually(session).Should(gexec.Exit(0))
+			output := string(session.Out.Contents())
+			Expect(output).To(ContainSubstring("Branch:  main (no upstream)"))
+			Expect(output).To(ContainSubstring("Ticket:  none detected in branch name"))
+			Expect(output).NotTo(ContainSubstring("Commits:"))
+		})
+	})
+
+	Context("when Jira can't find the ticket", func() {
+		BeforeEach(func() {
+			git("checkout", "-q", "-b", "ABC-404-missing")
+		})
+
+		It("should show the key and the error", func() {
+			session := runStatusCommand()
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("Ticket:  ABC-404 (could not fetch details:"))
+		})
+	})
+})
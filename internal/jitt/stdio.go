@@ -0,0 +1,28 @@
+package jitt
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// IO is where a handler reads its input and writes its output, so handlers
+// can run in-process with buffers in tests or inside larger commands
+type IO struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+}
+
+// StdIO returns the process's standard input, output and error
+func StdIO() IO {
+	return IO{In: os.Stdin, Out: os.Stdout, Err: os.Stderr}
+}
+
+// reader returns In wrapped for line-by-line prompts
+func (s IO) reader() *bufio.Reader {
+	if r, ok := s.In.(*bufio.Reader); ok {
+		return r
+	}
+	return bufio.NewReader(s.In)
+}
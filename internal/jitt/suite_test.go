@@ -1,9 +1,15 @@
 package jitt
 
 import (
+	"os"
+	"os/exec"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
+
+	"github.com/bbommarito/jitt/internal/git/gittest"
 )
 
 var pathToJittBinary string
@@ -18,3 +24,29 @@ var _ = BeforeSuite(func() {
 var _ = AfterSuite(func() {
 	gexec.CleanupBuildArtifacts()
 })
+
+// initGitRepo turns the current directory into a real Git repository on
+// branch main with a local identity, so git commands run by jitt succeed
+func initGitRepo() *gittest.Repo {
+	return gittest.Init(GinkgoT(), ".")
+}
+
+// git runs a git command in the current directory and returns its trimmed output
+func git(args ...string) string {
+	out, err := exec.Command("git", args...).CombinedOutput()
+	Expect(err).NotTo(HaveOccurred(), "git %v: %s", args, out)
+	return strings.TrimSpace(string(out))
+}
+
+// commit creates an empty commit with the given message
+func commit(message string) {
+	git("commit", "-q", "--allow-empty", "-m", message)
+}
+
+// commitAt creates an empty commit with the given message and author/committer date
+func commitAt(message, date string) {
+	cmd := exec.Command("git", "commit", "-q", "--allow-empty", "-m", message)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+date, "GIT_COMMITTER_DATE="+date)
+	out, err := cmd.CombinedOutput()
+	Expect(err).NotTo(HaveOccurred(), "git commit: %s", out)
+}
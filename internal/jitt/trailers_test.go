@@ -0,0 +1,60 @@
+package jitt
+
+import (
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+var _ = Describe("commit.position: trailer", func() {
+	var oldCwd string
+
+	BeforeEach(func() {
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+
+		initGitRepo()
+		Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\ncommit:\n  position: trailer\n  trailer: Jira\n"), 0o600)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should require the ticket in a trailer", func() {
+		session := runValidateMessage("ABC-1 Fix login\n")
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring(
+			"commit message has no Jira: trailer referencing a Jira ticket (e.g. Jira: ABC-123)"))
+
+		Eventually(runValidateMessage("Fix login\n\nJira: ABC-1\n")).Should(gexec.Exit(0))
+	})
+
+	Describe("prepare-commit-msg hook", func() {
+		BeforeEach(func() {
+			commit("Initial commit\n\nJira: ABC-1")
+			git("checkout", "-q", "-b", "feature/ABC-42-login")
+		})
+
+		It("should append the branch's ticket as a trailer before git's comments", func() {
+			Expect(os.WriteFile("COMMIT_EDITMSG", []byte("Fix login\n# Please enter the commit message\n"), 0o600)).To(Succeed())
+
+			Eventually(runHookCommand("", "prepare-commit-msg", "COMMIT_EDITMSG", "message")).Should(gexec.Exit(0))
+			Expect(os.ReadFile("COMMIT_EDITMSG")).To(Equal([]byte("Fix login\n\nJira: ABC-42\n# Please enter the commit message\n")))
+
+			Eventually(runHookCommand("", "prepare-commit-msg", "COMMIT_EDITMSG", "message")).Should(gexec.Exit(0))
+			Expect(os.ReadFile("COMMIT_EDITMSG")).To(Equal([]byte("Fix login\n\nJira: ABC-42\n# Please enter the commit message\n")))
+		})
+
+		It("should leave merge messages alone", func() {
+			Expect(os.WriteFile("MERGE_MSG", []byte("Merge branch 'main'\n"), 0o600)).To(Succeed())
+
+			Eventually(runHookCommand("", "prepare-commit-msg", "MERGE_MSG", "merge")).Should(gexec.Exit(0))
+			Expect(os.ReadFile("MERGE_MSG")).To(Equal([]byte("Merge branch 'main'\n")))
+		})
+	})
+})
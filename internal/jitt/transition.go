@@ -0,0 +1,108 @@
+package jitt
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bbommarito/jitt/internal/jira"
+)
+
+// HandleTransition handles the 'jitt transition' command
+func HandleTransition(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("transition", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	dryRun := fs.Bool("dry-run", false, "show what would happen without changing the issue")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagError(err)
+	}
+
+	if len(positional) != 2 {
+		return usageError("Usage: jitt transition <ticket> <transition-or-status> [--dry-run]")
+	}
+
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+
+	client, err := newJiraClient(cfg)
+	if err != nil {
+		return configError("Error: %v", err)
+	}
+
+	key := strings.ToUpper(positional[0])
+	if err := transitionIssue(context.Background(), stdio.Out, client, key, positional[1], *dryRun || cfg.Workflow.DryRun); err != nil {
+		return jiraError(err, "Error: %v", err)
+	}
+	return nil
+}
+
+// transitionIssue moves an issue to target, which may name either a
+// transition or the status it leads to. It does nothing when the issue is
+// already in the target status, so workflow hooks can fire repeatedly.
+func transitionIssue(ctx context.Context, out io.Writer, client *jira.Client, key, target string, dryRun bool) error {
+	issue, err := client.Issue(ctx, key)
+	if err != nil {
+		return fmt.Errorf("fetching %s: %w", key, err)
+	}
+
+	current := issue.Fields.Status.Name
+	if strings.EqualFold(current, target) {
+		fmt.Fprintf(out, "%s is already in %s — nothing to do\n", key, current)
+		return nil
+	}
+
+	transitions, err := client.Transitions(ctx, key)
+	if err != nil {
+		return fmt.Errorf("listing transitions for %s: %w", key, err)
+	}
+
+	transition, found := findTransition(transitions, target)
+	if !found {
+		return fmt.Errorf("no transition %q available for %s in status %s (available: %s)",
+			target, key, current, transitionNames(transitions))
+	}
+
+	if strings.EqualFold(transition.To.Name, current) {
+		fmt.Fprintf(out, "%s is already in %s — nothing to do\n", key, current)
+		return nil
+	}
+
+	if dryRun {
+		fmt.Fprintf(out, "[dry-run] would transition %s from %s to %s\n", key, current, transition.To.Name)
+		return nil
+	}
+
+	if err := client.DoTransition(ctx, key, transition.ID); err != nil {
+		return fmt.Errorf("transitioning %s: %w", key, err)
+	}
+
+	fmt.Fprintf(out, "Transitioned %s from %s to %s\n", key, current, transition.To.Name)
+	return nil
+}
+
+// findTransition looks a transition up by its name or by the name of its target status
+func findTransition(transitions []jira.Transition, target string) (jira.Transition, bool) {
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, target) || strings.EqualFold(t.To.Name, target) {
+			return t, true
+		}
+	}
+	return jira.Transition{}, false
+}
+
+func transitionNames(transitions []jira.Transition) string {
+	if len(transitions) == 0 {
+		return "none"
+	}
+
+	names := make([]string, 0, len(transitions))
+	for _, t := range transitions {
+		names = append(names, t.Name)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,130 @@
+package jitt
+
+import (
+	"os"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+func runTransitionCommand(args ...string) *gexec.Session {
+	command := exec.Command(pathToJittBinary, append([]string{"transition"}, args...)...)
+	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+	return session
+}
+
+var _ = Describe("jitt transition command", func() {
+	var (
+		tmpDir string
+		oldCwd string
+		fake   *fakeJira
+	)
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		initGitRepo()
+		fake = newFakeJira()
+		fake.addIssue("ABC-123", "In Progress")
+	})
+
+	AfterEach(func() {
+		fake.Close()
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should show usage when arguments are missing", func() {
+		session := runTransitionCommand("ABC-123")
+
+		Eventually(session).Should(gexec.Exit(ExitUsage))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("Usage: jitt transition <ticket>"))
+	})
+
+	Context("with Jira configured", func() {
+		BeforeEach(func() {
+			Expect(fake.writeConfig("")).To(Succeed())
+		})
+
+		It("should transition by target status name", func() {
+			session := runTransitionCommand("ABC-123", "In Review")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("Transitioned ABC-123 from In Progress to In Review"))
+			Expect(fake.status("ABC-123")).To(Equal("In Review"))
+		})
+
+		It("should transition by transition name, case-insensitively", func() {
+			session := runTransitionCommand("abc-123", "resolve")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(fake.status("ABC-123")).To(Equal("Done"))
+		})
+
+		It("should do nothing when the issue is already in the target status", func() {
+			session := runTransitionCommand("ABC-123", "In Progress")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("already in In Progress"))
+			Expect(fake.Transitioned()).To(BeEmpty())
+		})
+
+		It("should not change anything in dry-run mode", func() {
+			session := runTransitionCommand("ABC-123", "Done", "--dry-run")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("[dry-run] would transition ABC-123 from In Progress to Done"))
+			Expect(fake.Transitioned()).To(BeEmpty())
+		})
+
+		It("should list available transitions when the target is unknown", func() {
+			session := runTransitionCommand("ABC-123", "Shipped")
+
+			Eventually(session).Should(gexec.Exit(1))
+			output := string(session.Err.Contents())
+			Expect(output).To(ContainSubstring(`no transition "Shipped" available for ABC-123`))
+			Expect(output).To(ContainSubstring("Submit for Review, Resolve"))
+		})
+
+		It("should report issues Jira does not know", func() {
+			session := runTransitionCommand("ABC-999", "Done")
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("fetching ABC-999"))
+		})
+	})
+
+	Context("with workflow.dry_run enabled", func() {
+		BeforeEach(func() {
+			Expect(fake.writeConfig("workflow:\n  dry_run: true\n")).To(Succeed())
+		})
+
+		It("should behave as a dry run", func() {
+			session := runTransitionCommand("ABC-123", "Done")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("[dry-run]"))
+			Expect(fake.Transitioned()).To(BeEmpty())
+		})
+	})
+
+	Context("without a Jira URL", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC"), 0o600)).To(Succeed())
+		})
+
+		It("should explain what is missing", func() {
+			session := runTransitionCommand("ABC-123", "Done")
+
+			Eventually(session).Should(gexec.Exit(ExitConfig))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("jira.url is not configured"))
+		})
+	})
+})
@@ -0,0 +1,324 @@
+package jitt
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// HandleValidate handles the 'jitt validate' command
+func HandleValidate(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	fix := fs.Bool("fix", false, "rewrite the message into the canonical commit.format before validating")
+	rangeSpec := fs.String("range", "", "validate the messages of the commits in a range instead of a message file")
+	report := fs.String("report", "", "also write a report of the range: sarif, junit or checkstyle")
+	reportFile := fs.String("report-file", "", "where the report is written (default: jitt.sarif, jitt-junit.xml or jitt-checkstyle.xml)")
+	configRev := fs.String("config-rev", "", "apply the .jitt.yaml files committed at this revision instead of the working tree's")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return flagError(err)
+	}
+
+	if *rangeSpec == "" && (len(positional) != 1 || *report != "" || *reportFile != "") ||
+		*rangeSpec != "" && (len(positional) != 0 || *fix) {
+		return usageError("Usage: jitt validate [--fix] [--config-rev <rev>] <commit-message-file|->\n" +
+			"       jitt validate --range <range> [--config-rev <rev>] [--report sarif|junit|checkstyle] [--report-file <file>]")
+	}
+
+	cfg, scopes, err := loadCheckConfig(*configRev)
+	if err != nil {
+		return err
+	}
+
+	if *rangeSpec != "" {
+		return validateRange(stdio, cfg, scopes, *rangeSpec, *report, *reportFile)
+	}
+	return validateMessageFile(stdio, cfg, scopes, positional[0], *fix)
+}
+
+// validateRange validates every commit in rangeSpec, printing the problems and
+// optionally writing them as a report, and fails if there are errors
+func validateRange(stdio IO, cfg *config.Config, scopes scopeFunc, rangeSpec, report, reportFile string) error {
+	var write func(io.Writer, []checkResult) error
+	var defaultFile string
+	switch report {
+	case "":
+	case "sarif":
+		write, defaultFile = writeSARIF, "jitt.sarif"
+	case reportJUnit:
+		write, defaultFile = writeJUnit, "jitt-junit.xml"
+	case "checkstyle":
+		write, defaultFile = writeCheckstyle, "jitt-checkstyle.xml"
+	default:
+		return usageError("Unknown report format: %s (use sarif, junit or checkstyle)", report)
+	}
+
+	// Bypasses are judged against the branch the range is checked on
+	branch, _ := currentBranch()
+	results, err := checkCommits(context.Background(), stdio.Err, cfg, scopes, branch, rangeSpec)
+	if err != nil {
+		return failure("Error reading commits in %s: %v", rangeSpec, err)
+	}
+	writeTextReport(stdio.Out, results)
+
+	if write != nil {
+		err := writeReportFile(stdio.Out, reportFile, defaultFile, func(w io.Writer) error {
+			return write(w, results)
+		})
+		if err != nil {
+			return failure("Error writing report: %v", err)
+		}
+	}
+
+	if errors, _ := countProblems(results); errors > 0 {
+		return &Error{Code: ExitFailure}
+	}
+	return nil
+}
+
+// validateMessageFile validates the commit message in path ("-" for stdin)
+// against the configuration scopes finds for the staged files, printing any
+// problems and failing if there are errors. Commits excused by
+// validate.exempt or bypassed pass unchecked. With fix, the message is first
+// rewritten into the canonical format: in place for a file, or printed to
+// stdout when read from stdin.
+func validateMessageFile(stdio IO, cfg *config.Config, scopes scopeFunc, path string, fix bool) error {
+	raw, err := readRawMessage(stdio.In, path)
+	if err != nil {
+		return failure("Error reading commit message: %v", err)
+	}
+	message := lib.StripComments(raw)
+
+	// Outside a commit (e.g. validating a message from stdin) nothing may be staged
+	files, _ := stagedFiles()
+	if reason, ok := lib.ExemptReason(cfg, pendingCommit(files), message); ok {
+		fmt.Fprintf(stdio.Err, "⏭️  jitt checks skipped: exempt (%s)\n", reason)
+		return nil
+	}
+
+	if reason := os.Getenv(bypassEnv); reason != "" || hasBypass(message) {
+		return bypassMessageFile(stdio.Err, cfg, path, raw, message, reason)
+	}
+
+	if fix {
+		if message, err = fixMessageFile(stdio, cfg, path, raw, message); err != nil {
+			return failure("Error fixing commit message: %v", err)
+		}
+	}
+
+	failed := false
+	for _, problem := range validateScopes(context.Background(), stdio.Err, cfg, scopes, message, files) {
+		if problem.Severity == config.SeverityWarning {
+			fmt.Fprintf(stdio.Err, "⚠️  %s\n", problem)
+			continue
+		}
+		fmt.Fprintf(stdio.Err, "❌ %s\n", problem)
+		failed = true
+	}
+	if failed {
+		return &Error{Code: ExitFailure}
+	}
+	return nil
+}
+
+// pendingCommit describes the commit being made, touching files, as far as
+// the commit-msg hook can tell: git sets the identities in its environment
+func pendingCommit(files []string) lib.CommitInfo {
+	ctx := context.Background()
+	_, author, _ := repo.Ident(ctx, "AUTHOR")
+	_, committer, _ := repo.Ident(ctx, "COMMITTER")
+	return lib.CommitInfo{
+		AuthorEmail:    author,
+		CommitterEmail: committer,
+		Files:          files,
+		Merge:          repo.Merging(ctx),
+	}
+}
+
+// hasBypass reports whether message carries a bypass reason
+func hasBypass(message string) bool {
+	_, ok := lib.BypassReason(message)
+	return ok
+}
+
+// bypassMessageFile lets a commit skip the checks with reason (from
+// JITT_SKIP, or else the message's own marker or trailer), recording the
+// reason in a Jitt-Skip trailer, unless the current branch disallows bypasses
+func bypassMessageFile(w io.Writer, cfg *config.Config, path, raw, message, reason string) error {
+	if reason == "" {
+		reason, _ = lib.BypassReason(message)
+	}
+
+	branch, _ := currentBranch()
+	if !lib.BypassAllowed(cfg, branch) {
+		return failure("❌ %s", bypassProblem(branch))
+	}
+
+	if path == "-" {
+		fmt.Fprintf(w, "⚠️  jitt checks skipped: %s\n", reason)
+		return nil
+	}
+	if recorded := lib.RecordBypass(message, reason); recorded != message {
+		content := recorded + "\n"
+		if tail := messageTail(raw); tail != "" {
+			content += "\n" + tail
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return failure("Error recording the bypass: %v", err)
+		}
+	}
+	fmt.Fprintf(w, "⚠️  jitt checks skipped: %s (recorded in a %s trailer)\n", reason, lib.BypassTrailer)
+	return nil
+}
+
+// fixMessageFile applies lib.FixMessage and writes the result back, reporting
+// each change. It returns the message to validate.
+func fixMessageFile(stdio IO, cfg *config.Config, path, raw, message string) (string, error) {
+	branch, _ := currentBranch()
+	fixed, changes, err := lib.FixMessage(cfg, message, branch)
+	if err != nil {
+		return "", err
+	}
+
+	if path == "-" {
+		fmt.Fprintln(stdio.Out, fixed)
+		for _, change := range changes {
+			fmt.Fprintf(stdio.Err, "fixed: %s\n", change)
+		}
+		return fixed, nil
+	}
+
+	if len(changes) == 0 {
+		return fixed, nil
+	}
+
+	content := fixed + "\n"
+	if tail := messageTail(raw); tail != "" {
+		content += "\n" + tail
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+
+	fmt.Fprintf(stdio.Out, "Fixed %s:\n", path)
+	for _, change := range changes {
+		fmt.Fprintf(stdio.Out, "  - %s\n", change)
+	}
+	return fixed, nil
+}
+
+// scopeFunc groups the files a commit touches by the configuration applying
+// to them, so directories with their own .jitt.yaml enforce their own rules
+type scopeFunc func(files []string) ([]config.Scope, error)
+
+// workingTreeScopes reads nested .jitt.yaml files from the working tree, or
+// returns nil outside one
+func workingTreeScopes() scopeFunc {
+	root, err := runGit("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil
+	}
+	return func(files []string) ([]config.Scope, error) {
+		return config.Resolve(root, files)
+	}
+}
+
+// treeScopes reads nested .jitt.yaml files from a committed tree
+func treeScopes(tree fs.FS) scopeFunc {
+	return func(files []string) ([]config.Scope, error) {
+		return config.ResolveFS(tree, files)
+	}
+}
+
+// validateScopes validates the message against the configuration scopes
+// finds for each group of files. Without files or scopes, the root
+// configuration cfg applies. Jira lookups that fail are reported to warn.
+func validateScopes(ctx context.Context, warn io.Writer, cfg *config.Config, scopes scopeFunc, message string, files []string) []lib.Problem {
+	if scopes == nil || len(files) == 0 {
+		return validateMessage(ctx, warn, cfg, message, files)
+	}
+
+	resolved, err := scopes(files)
+	if err != nil {
+		return []lib.Problem{{Rule: "config", Severity: config.SeverityError,
+			Message: fmt.Sprintf("invalid configuration: %v", err)}}
+	}
+	if len(resolved) == 1 {
+		return validateMessage(ctx, warn, resolved[0].Config, message, files)
+	}
+
+	var problems []lib.Problem
+	for _, scope := range resolved {
+		source := scope.Files[len(scope.Files)-1]
+		for _, problem := range validateMessage(ctx, warn, scope.Config, message, scope.Paths) {
+			problem.Message = fmt.Sprintf("%s: %s", source, problem.Message)
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// validateMessage returns the problems found in the message of a commit
+// touching files, asking Jira about smart-commit transitions when configured
+func validateMessage(ctx context.Context, warn io.Writer, cfg *config.Config, message string, files []string) []lib.Problem {
+	v := lib.Validator{Config: cfg}
+	if cfg.SmartCommits.Mode == config.SmartCommitsOff {
+		return v.ValidateMessage(ctx, message, files)
+	}
+	if client, err := newJiraClient(cfg); err == nil {
+		v.Transitions = func(ctx context.Context, key string) ([]lib.Transition, error) {
+			transitions, err := client.Transitions(ctx, key)
+			if err != nil {
+				fmt.Fprintf(warn, "jitt: could not check transitions for %s: %v\n", key, err)
+				return nil, err
+			}
+			available := make([]lib.Transition, 0, len(transitions))
+			for _, t := range transitions {
+				available = append(available, lib.Transition{Name: t.Name, Status: t.To.Name})
+			}
+			return available, nil
+		}
+	}
+	return v.ValidateMessage(ctx, message, files)
+}
+
+// readRawMessage reads a commit message file, or stdin for "-"
+func readRawMessage(stdin io.Reader, path string) (string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return string(data), err
+}
+
+// messageTail returns what follows the message in a commit message file: the
+// trailing comment lines git adds and the verbose diff below the scissors line
+func messageTail(raw string) string {
+	head, diff, hasDiff := strings.Cut(raw, lib.ScissorsLine)
+
+	lines := strings.Split(strings.TrimRight(head, "\n"), "\n")
+	start := len(lines)
+	for start > 0 && (lines[start-1] == "" || strings.HasPrefix(lines[start-1], "#")) {
+		start--
+	}
+
+	tail := strings.TrimLeft(strings.Join(lines[start:], "\n"), "\n")
+	if tail != "" {
+		tail += "\n"
+	}
+	if hasDiff {
+		tail += lib.ScissorsLine + diff
+	}
+	return tail
+}
@@ -0,0 +1,484 @@
+package jitt
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+
+	"github.com/bbommarito/jitt/internal/git/gittest"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// runValidateMessage writes message to a file and runs 'jitt validate' on it
+func runValidateMessage(message string) *gexec.Session {
+	Expect(os.WriteFile("COMMIT_EDITMSG", []byte(message), 0o600)).To(Succeed())
+	command := exec.Command(pathToJittBinary, "validate", "COMMIT_EDITMSG")
+	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+	return session
+}
+
+// runValidate runs 'jitt validate' with args, adding env to the environment
+func runValidate(env []string, args ...string) *gexec.Session {
+	command := exec.Command(pathToJittBinary, append([]string{"validate"}, args...)...)
+	command.Env = append(os.Environ(), env...)
+	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+	return session
+}
+
+var _ = Describe("jitt validate command", func() {
+	var (
+		tmpDir string
+		oldCwd string
+	)
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		initGitRepo()
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should show usage without a message file", func() {
+		command := exec.Command(pathToJittBinary, "validate")
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(ExitUsage))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("Usage: jitt validate"))
+	})
+
+	Context("with a project configured", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC"), 0o600)).To(Succeed())
+		})
+
+		It("should accept a message referencing a ticket", func() {
+			session := runValidateMessage("ABC-123 Fix the login form\n")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(session.Err.Contents()).To(BeEmpty())
+		})
+
+		It("should reject a message without a ticket", func() {
+			session := runValidateMessage("Fix the login form\n")
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("does not reference a Jira ticket (e.g. ABC-123)"))
+		})
+
+		It("should reject tickets from other projects", func() {
+			session := runValidateMessage("XYZ-9 Fix the login form\n")
+
+			Eventually(session).Should(gexec.Exit(1))
+		})
+
+		It("should ignore git's comment lines and verbose diff", func() {
+			session := runValidateMessage("Fix the login form\n# ABC-1 in a comment\n" +
+				lib.ScissorsLine + "\ndiff --git a/ABC-2 b/ABC-2\n")
+
+			Eventually(session).Should(gexec.Exit(1))
+		})
+
+		It("should read the message from stdin", func() {
+			command := exec.Command(pathToJittBinary, "validate", "-")
+			command.Stdin = strings.NewReader("ABC-7 Read from stdin")
+			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(session).Should(gexec.Exit(0))
+		})
+
+		Describe("smart commits", func() {
+			It("should accept well-formed commands", func() {
+				session := runValidateMessage("ABC-123 #comment fixed null check #time 1w 2d 4h 30m pairing #resolve\n")
+
+				Eventually(session).Should(gexec.Exit(0))
+			})
+
+			It("should reject malformed #time values", func() {
+				session := runValidateMessage("ABC-123 fix it\n\nABC-123 #time 1.5h\n")
+
+				Eventually(session).Should(gexec.Exit(1))
+				Expect(string(session.Err.Contents())).To(ContainSubstring(`line 3: invalid #time value "1.5h"`))
+			})
+
+			It("should reject empty comments", func() {
+				session := runValidateMessage("ABC-123 fix it #comment\n")
+
+				Eventually(session).Should(gexec.Exit(1))
+				Expect(string(session.Err.Contents())).To(ContainSubstring("#comment needs some text"))
+			})
+
+			It("should ignore hashtags on lines without a ticket", func() {
+				session := runValidateMessage("ABC-123 fix it\n\nSee #time-travel and #42\n")
+
+				Eventually(session).Should(gexec.Exit(0))
+			})
+		})
+	})
+
+	Context("with paths mapped to projects", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"+
+				"projects:\n"+
+				"  - paths: [services/billing/**]\n    keys: [BILL]\n"+
+				"  - paths: [web/**]\n    keys: [WEB]\n"), 0o600)).To(Succeed())
+			Expect(os.MkdirAll("services/billing", 0o755)).To(Succeed())
+			Expect(os.MkdirAll("web", 0o755)).To(Succeed())
+			Expect(os.WriteFile("services/billing/invoice.go", []byte("package billing\n"), 0o600)).To(Succeed())
+			Expect(os.WriteFile("web/index.html", []byte("<html></html>\n"), 0o600)).To(Succeed())
+			Expect(os.WriteFile("README.md", []byte("# readme\n"), 0o600)).To(Succeed())
+		})
+
+		It("should require a ticket of the project owning the staged files", func() {
+			git("add", "services/billing/invoice.go")
+
+			session := runValidateMessage("ABC-1 tweak invoices\n")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring(
+				"commit touches files owned by BILL but references none of their tickets (e.g. BILL-123); found ABC-1"))
+
+			Eventually(runValidateMessage("BILL-7 tweak invoices\n")).Should(gexec.Exit(0))
+		})
+
+		It("should accept a ticket of any owning project when files span areas", func() {
+			git("add", "services/billing/invoice.go", "web/index.html")
+
+			Eventually(runValidateMessage("WEB-3 show invoices\n")).Should(gexec.Exit(0))
+			Eventually(runValidateMessage("BILL-3 show invoices\n")).Should(gexec.Exit(0))
+		})
+
+		It("should fall back to the default project for unmapped files", func() {
+			git("add", "README.md")
+
+			Eventually(runValidateMessage("ABC-1 docs\n")).Should(gexec.Exit(0))
+			Eventually(runValidateMessage("docs\n")).Should(gexec.Exit(1))
+		})
+	})
+
+	Context("with nested .jitt.yaml files", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"), 0o600)).To(Succeed())
+			Expect(os.MkdirAll("services/billing", 0o755)).To(Succeed())
+			Expect(os.WriteFile("services/billing/.jitt.yaml", []byte("jira:\n  project: BILL\n"), 0o600)).To(Succeed())
+			Expect(os.WriteFile("services/billing/invoice.go", []byte("package billing\n"), 0o600)).To(Succeed())
+			Expect(os.WriteFile("README.md", []byte("# readme\n"), 0o600)).To(Succeed())
+		})
+
+		It("should apply the nested configuration to files below it", func() {
+			git("add", "services/billing/invoice.go")
+
+			session := runValidateMessage("ABC-1 tweak invoices\n")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("(e.g. BILL-123)"))
+
+			Eventually(runValidateMessage("BILL-7 tweak invoices\n")).Should(gexec.Exit(0))
+		})
+
+		It("should check each directory's rules when a commit spans them", func() {
+			git("add", "services/billing/invoice.go", "README.md")
+
+			session := runValidateMessage("BILL-7 tweak invoices\n")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring(".jitt.yaml: commit message does not reference a Jira ticket (e.g. ABC-123)"))
+			Expect(string(session.Err.Contents())).NotTo(ContainSubstring("services/billing/.jitt.yaml:"))
+
+			Eventually(runValidateMessage("BILL-7 ABC-2 tweak invoices\n")).Should(gexec.Exit(0))
+		})
+
+		It("should check a merge against the rules of the files it brings in", func() {
+			git("add", ".")
+			commit("ABC-1 Add configs")
+			git("checkout", "-q", "-b", "billing")
+			Expect(os.WriteFile("services/billing/invoice.go", []byte("package billing\n\n// Total\n"), 0o600)).To(Succeed())
+			git("commit", "-q", "-am", "BILL-2 Document invoices")
+			git("checkout", "-q", "main")
+			git("merge", "-q", "--no-ff", "-m", "ABC-3 Merge the billing work", "billing")
+
+			session, err := gexec.Start(exec.Command(pathToJittBinary, "validate", "--range", "HEAD^..HEAD"), GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(session).Should(gexec.Exit(1))
+			output := session.Out.Contents()
+			Expect(string(output)).To(MatchRegexp(`ABC-3 Merge the billing work\n  ❌ .*\(e\.g\. BILL-123\)`))
+			Expect(string(output)).To(ContainSubstring("Checked 2 items: 1 errors"))
+		})
+	})
+
+	Context("with allowed smart-commit transitions listed", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"+
+				"smart_commits:\n  transitions: [resolve, start-progress]\n"), 0o600)).To(Succeed())
+		})
+
+		It("should accept listed transitions", func() {
+			session := runValidateMessage("ABC-1 begin #start-progress\n")
+
+			Eventually(session).Should(gexec.Exit(0))
+		})
+
+		It("should reject unlisted transitions", func() {
+			session := runValidateMessage("ABC-1 ship it #deploy\n")
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("unknown transition #deploy (allowed: resolve, start-progress)"))
+		})
+	})
+
+	Context("with smart commits turned off", func() {
+		BeforeEach(func() {
+			Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: ABC\n"+
+				"smart_commits:\n  mode: off\n  transitions: [resolve]\n"), 0o600)).To(Succeed())
+		})
+
+		It("should not check commands", func() {
+			session := runValidateMessage("ABC-1 ship it #deploy #time soon\n")
+
+			Eventually(session).Should(gexec.Exit(0))
+		})
+	})
+
+	Context("with Jira configured", func() {
+		var fake *fakeJira
+
+		BeforeEach(func() {
+			fake = newFakeJira()
+			fake.addIssue("ABC-1", "In Progress")
+			Expect(fake.writeConfig("")).To(Succeed())
+		})
+
+		AfterEach(func() {
+			fake.Close()
+		})
+
+		It("should accept transitions Jira offers", func() {
+			session := runValidateMessage("ABC-1 done #submit-for-review\n")
+
+			Eventually(session).Should(gexec.Exit(0))
+		})
+
+		It("should reject transitions Jira does not offer", func() {
+			session := runValidateMessage("ABC-1 done #close\n")
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("unknown transition #close for ABC-1"))
+		})
+	})
+})
+
+var _ = Describe("jitt validate --config-rev", func() {
+	var (
+		oldCwd string
+		repo   *gittest.Repo
+	)
+
+	BeforeEach(func() {
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+
+		repo = initGitRepo()
+		repo.File(".jitt.yaml", "jira:\n  project: ABC\n").
+			File("services/billing/.jitt.yaml", "jira:\n  project: BILL\n").
+			Commit("ABC-1 Add jitt config")
+		repo.Branch("feature")
+		// The branch loosens the rules it is checked against
+		repo.File(".jitt.yaml", "jira:\n  project: XYZ\n").Remove("services/billing/.jitt.yaml").Commit("XYZ-1 Relax the rules")
+		repo.File("services/billing/invoice.go", "package billing\n").Commit("XYZ-2 Tweak invoices")
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should apply the rules committed at the revision, not the branch's own", func() {
+		Eventually(runValidate(nil, "--range", "main..HEAD")).Should(gexec.Exit(0))
+
+		session := runValidate(nil, "--range", "main..HEAD", "--config-rev", "main")
+		Eventually(session).Should(gexec.Exit(1))
+		output := string(session.Out.Contents())
+		Expect(output).To(ContainSubstring("XYZ-1 Relax the rules"))
+		Expect(output).To(ContainSubstring("(e.g. BILL-123)"))
+		Expect(output).To(ContainSubstring("Checked 2 items: 3 errors"))
+	})
+
+	It("should validate a message file against the revision's rules", func() {
+		Expect(os.WriteFile("COMMIT_EDITMSG", []byte("XYZ-3 Quick fix\n"), 0o600)).To(Succeed())
+
+		Eventually(runValidate(nil, "COMMIT_EDITMSG")).Should(gexec.Exit(0))
+		session := runValidate(nil, "--config-rev", "main", "COMMIT_EDITMSG")
+		Eventually(session).Should(gexec.Exit(1))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("does not reference a Jira ticket (e.g. ABC-123)"))
+	})
+
+	It("should work without a .jitt.yaml in the working tree", func() {
+		Expect(os.Remove(".jitt.yaml")).To(Succeed())
+
+		Eventually(runValidate(nil, "--range", "main..HEAD", "--config-rev", "main")).Should(gexec.Exit(1))
+	})
+
+	It("should report revisions without a config", func() {
+		session := runValidate(nil, "--range", "main..HEAD", "--config-rev", "no-such-rev")
+
+		Eventually(session).Should(gexec.Exit(ExitConfig))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("Error loading config from no-such-rev"))
+	})
+})
+
+// The bypass and exemption specs share a repository whose config each sets up
+var _ = Describe("jitt validate overrides", func() {
+	var (
+		oldCwd string
+		repo   *gittest.Repo
+	)
+
+	BeforeEach(func() {
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+
+		repo = initGitRepo()
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	Describe("bypass", func() {
+		BeforeEach(func() {
+			repo.File(".jitt.yaml", "jira:\n  project: ABC\nbypass:\n  disabled_branches: [\"release/*\"]\n").
+				Commit("ABC-1 Add jitt config")
+		})
+
+		It("should let a commit through with JITT_SKIP and record the reason", func() {
+			Expect(os.WriteFile("COMMIT_EDITMSG", []byte("Stop the bleeding\n# Please enter the commit message\n"), 0o600)).To(Succeed())
+
+			session := runValidate([]string{"JITT_SKIP=prod is down"}, "COMMIT_EDITMSG")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("jitt checks skipped: prod is down"))
+			message, err := os.ReadFile("COMMIT_EDITMSG")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(message)).To(Equal("Stop the bleeding\n\nJitt-Skip: prod is down\n\n# Please enter the commit message\n"))
+		})
+
+		It("should let a commit through with a [skip-jitt: reason] marker", func() {
+			Expect(os.WriteFile("COMMIT_EDITMSG", []byte("Stop the bleeding [skip-jitt: prod is down]\n"), 0o600)).To(Succeed())
+
+			Eventually(runValidate(nil, "COMMIT_EDITMSG")).Should(gexec.Exit(0))
+			message, err := os.ReadFile("COMMIT_EDITMSG")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(message)).To(HaveSuffix("\n\nJitt-Skip: prod is down\n"))
+		})
+
+		It("should not accept a marker without a reason", func() {
+			Expect(os.WriteFile("COMMIT_EDITMSG", []byte("Stop the bleeding [skip-jitt:]\n"), 0o600)).To(Succeed())
+
+			Eventually(runValidate(nil, "COMMIT_EDITMSG")).Should(gexec.Exit(1))
+		})
+
+		It("should refuse bypasses on branches that disable them", func() {
+			repo.Branch("release/1.0")
+			Expect(os.WriteFile("COMMIT_EDITMSG", []byte("Stop the bleeding\n"), 0o600)).To(Succeed())
+
+			session := runValidate([]string{"JITT_SKIP=prod is down"}, "COMMIT_EDITMSG")
+
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Err.Contents())).To(ContainSubstring(`skipping jitt's checks is not allowed on branch release/1.0`))
+		})
+
+		It("should show recorded bypasses when checking a range, and reject them where disabled", func() {
+			repo.Commit("Stop the bleeding\n\nJitt-Skip: prod is down")
+
+			session := runValidate(nil, "--range", "HEAD~1..HEAD")
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("checks skipped: prod is down"))
+
+			repo.Branch("release/1.0")
+			session = runValidate(nil, "--range", "HEAD~1..HEAD")
+			Eventually(session).Should(gexec.Exit(1))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("not allowed on branch"))
+		})
+	})
+
+	Describe("exemptions", func() {
+		BeforeEach(func() {
+			repo.File(".jitt.yaml", "jira:\n  project: ABC\nvalidate:\n  exempt:\n"+
+				"    authors: [\"*[bot]@users.noreply.github.com\"]\n"+
+				"    paths: [\"docs/**\", \"*.md\"]\n    merges: true\n    reverts: true\n").
+				Commit("ABC-1 Add jitt config")
+			Expect(os.WriteFile("COMMIT_EDITMSG", []byte("Update dependencies\n"), 0o600)).To(Succeed())
+		})
+
+		It("should let bots commit without a ticket", func() {
+			session := runValidate([]string{"GIT_AUTHOR_EMAIL=29139614+renovate[bot]@users.noreply.github.com"}, "COMMIT_EDITMSG")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Err.Contents())).To(ContainSubstring("exempt (author 29139614+renovate[bot]@users.noreply.github.com matches"))
+			Eventually(runValidate(nil, "COMMIT_EDITMSG")).Should(gexec.Exit(1))
+		})
+
+		It("should exempt commits touching only exempt paths", func() {
+			repo.File("docs/setup.txt", "x\n").File("README.md", "x\n")
+			Eventually(runValidate(nil, "COMMIT_EDITMSG")).Should(gexec.Exit(0))
+
+			repo.File("main.go", "package main\n")
+			Eventually(runValidate(nil, "COMMIT_EDITMSG")).Should(gexec.Exit(1))
+		})
+
+		It("should exempt merges in progress", func() {
+			repo.Branch("topic").File("a.txt", "1\n").Commit("ABC-2 Topic work")
+			repo.Checkout("main").File("b.txt", "1\n").Commit("ABC-3 Main work")
+			repo.Git("merge", "--no-commit", "topic")
+
+			Eventually(runValidate(nil, "COMMIT_EDITMSG")).Should(gexec.Exit(0))
+		})
+
+		It("should apply the same exemptions to a range", func() {
+			work := repo.File("main.go", "package main\n").Commit("ABC-2 Add main")
+			repo.File("docs/setup.txt", "x\n").Commit("Document setup")
+			repo.Git("revert", "--no-edit", work)
+			repo.File("go.sum", "x\n").Commit("Update dependencies",
+				gittest.Author("renovate[bot]", "29139614+renovate[bot]@users.noreply.github.com"))
+			repo.File("app.go", "package main\n").Commit("Untracked work")
+
+			session := runValidate(nil, "--range", "HEAD~5..HEAD")
+
+			Eventually(session).Should(gexec.Exit(1))
+			output := string(session.Out.Contents())
+			Expect(output).To(ContainSubstring("Document setup\n  ⏭️  exempt: only touches exempt paths"))
+			Expect(output).To(ContainSubstring("exempt: revert commit"))
+			Expect(output).To(ContainSubstring("Update dependencies\n  ⏭️  exempt: author"))
+			Expect(output).To(ContainSubstring("Untracked work\n  ❌"))
+			Expect(output).To(ContainSubstring("Checked 5 items: 1 errors"))
+		})
+
+		It("should exempt a merge that brings in only exempt paths", func() {
+			repo.File(".jitt.yaml", "jira:\n  project: ABC\nvalidate:\n  exempt:\n    paths: [\"docs/**\"]\n").
+				Commit("ABC-2 Exempt only docs")
+			repo.Branch("docs").File("docs/setup.txt", "x\n").Commit("ABC-3 Document setup")
+			repo.Checkout("main").File("main.go", "package main\n").Commit("ABC-4 Add main")
+			repo.Merge("docs", "Merge the docs")
+
+			session := runValidate(nil, "--range", "HEAD^..HEAD")
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(MatchRegexp(`Merge the docs\n  ⏭️  exempt: only touches exempt paths`))
+		})
+	})
+})
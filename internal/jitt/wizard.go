@@ -0,0 +1,277 @@
+package jitt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// commonCommitFormats are the subject layouts the init wizard recognises in history
+var commonCommitFormats = []string{
+	"{key} {message}",
+	"{key}: {message}",
+	"[{key}] {message}",
+	"{message} ({key})",
+	"{message} [{key}]",
+}
+
+// projectCount is a project key seen in the repository and how often
+type projectCount struct {
+	Key   string
+	Count int
+}
+
+// repoHistory is what the init wizard learns from the repository
+type repoHistory struct {
+	Subjects []string
+	Branches []string
+}
+
+// readRepoHistory collects recent commit subjects from all refs and the
+// names of local and remote branches. A repository without commits has neither.
+func readRepoHistory() repoHistory {
+	var h repoHistory
+	if out, err := runGit("log", "--all", "-n", "1000", "--format=%s"); err == nil && out != "" {
+		h.Subjects = strings.Split(out, "\n")
+	}
+
+	out, _ := runGit("for-each-ref", "--format=%(refname:lstrip=2)", "refs/heads")
+	remote, _ := runGit("for-each-ref", "--format=%(refname:lstrip=3)", "refs/remotes")
+	for _, branch := range strings.Fields(out + "\n" + remote) {
+		if branch != "HEAD" && !containsString(h.Branches, branch) {
+			h.Branches = append(h.Branches, branch)
+		}
+	}
+	return h
+}
+
+// wizardConfig is the configuration the init wizard detects keys with: the
+// base config opts extends, if any, with the project given on the command line
+func wizardConfig(opts config.InitOptions) *config.Config {
+	cfg := config.Defaults()
+	if opts.Extends.Source != "" {
+		if f, err := os.Open(opts.Extends.BaseFile(".")); err == nil {
+			if base, err := config.Parse(f); err == nil {
+				cfg = base
+			}
+			_ = f.Close()
+		}
+	}
+	if opts.Project != "" {
+		cfg.Jira.Project = strings.ToUpper(opts.Project)
+	}
+	return cfg
+}
+
+// wizardKeyParser returns the parser the init wizard finds keys with: those
+// of any project cfg does not deny, within keys.min and keys.max. Keys of its
+// known projects count in any case in branch names.
+func wizardKeyParser(cfg *config.Config) lib.KeyParser {
+	keys := lib.NewKeyParser(cfg)
+	keys.AnyProject = true
+	keys.IgnoreCase = true
+	return keys
+}
+
+// detectProjects ranks the project keys used in commit subjects and branch
+// names, most frequent first. Branch names may write the keys of projects
+// known to keys, or seen in the subjects, in lower case.
+func (h repoHistory) detectProjects(keys lib.KeyParser) []projectCount {
+	counts := make(map[string]int)
+	subjectKeys := keys
+	subjectKeys.IgnoreCase = false
+	for _, subject := range h.Subjects {
+		for _, m := range subjectKeys.Parse(subject) {
+			counts[lib.KeyProject(m.Key)]++
+		}
+	}
+	branchKeys := keys
+	branchKeys.Projects = append([]string(nil), keys.Projects...)
+	for project := range counts {
+		branchKeys.Projects = append(branchKeys.Projects, project)
+	}
+	for _, branch := range h.Branches {
+		for _, m := range branchKeys.Parse(branch) {
+			counts[lib.KeyProject(m.Key)]++
+		}
+	}
+
+	projects := make([]projectCount, 0, len(counts))
+	for key, n := range counts {
+		projects = append(projects, projectCount{Key: key, Count: n})
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Count != projects[j].Count {
+			return projects[i].Count > projects[j].Count
+		}
+		return projects[i].Key < projects[j].Key
+	})
+	return projects
+}
+
+// detectCommitFormat returns the common layout most subjects with a ticket
+// key follow, or "" when none does
+func (h repoHistory) detectCommitFormat() string {
+	best, bestCount := "", 0
+	for _, format := range commonCommitFormats {
+		pattern, err := lib.CommitFormatPattern(format)
+		if err != nil {
+			continue
+		}
+		n := 0
+		for _, subject := range h.Subjects {
+			if pattern.MatchString(subject) {
+				n++
+			}
+		}
+		if n > bestCount {
+			best, bestCount = format, n
+		}
+	}
+	return best
+}
+
+// detectBranchPattern builds a branch.pattern matching the branches named
+// after tickets, keeping the prefixes (feature/, bugfix/, ...) in use. It
+// returns "" when no branch names a ticket keys recognises.
+func (h repoHistory) detectBranchPattern(keys lib.KeyParser) string {
+	var prefixes []string
+	withoutPrefix, ignoreCase, found := false, false, false
+	for _, branch := range h.Branches {
+		matches := keys.Parse(branch)
+		if len(matches) == 0 {
+			continue
+		}
+		found = true
+		start, end := matches[0].Start, matches[0].End
+		if key := branch[start:end]; key != strings.ToUpper(key) {
+			ignoreCase = true
+		}
+
+		prefix := strings.TrimSuffix(branch[:start], "/")
+		switch {
+		case start == 0:
+			withoutPrefix = true
+		case prefix != "" && !strings.Contains(prefix, "/") && !containsString(prefixes, prefix):
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	if !found {
+		return ""
+	}
+
+	pattern := "^"
+	if ignoreCase {
+		pattern = "(?i)^"
+	}
+	if len(prefixes) > 0 {
+		sort.Strings(prefixes)
+		for i, prefix := range prefixes {
+			prefixes[i] = regexp.QuoteMeta(prefix)
+		}
+		group := "(" + strings.Join(prefixes, "|") + ")/"
+		if withoutPrefix {
+			group = "(" + group + ")?"
+		}
+		pattern += group
+	}
+	return pattern + "[A-Z][A-Z0-9_]+-[0-9]+"
+}
+
+// runInitWizard asks for each setting, offering opts (from flags) or what was
+// detected in the repository as the default answers. With acceptDefaults it
+// asks nothing. Questions go to out. It returns the answers and whether to install hooks.
+func runInitWizard(in *bufio.Reader, out io.Writer, opts config.InitOptions, installHooks, acceptDefaults bool) (config.InitOptions, bool) {
+	history := readRepoHistory()
+	ask := func(question, def string) string {
+		if acceptDefaults {
+			return def
+		}
+		answer, ok := prompt(in, out, fmt.Sprintf("%s [%s]: ", question, def))
+		switch {
+		case !ok || answer == "":
+			return def
+		case answer == "-":
+			return ""
+		}
+		return answer
+	}
+
+	keys := wizardKeyParser(wizardConfig(opts))
+	projects := history.detectProjects(keys)
+	if opts.Project == "" && len(projects) > 0 {
+		opts.Project = projects[0].Key
+	}
+	if len(projects) > 0 {
+		seen := make([]string, 0, 3)
+		for _, p := range projects[:min(len(projects), 3)] {
+			seen = append(seen, fmt.Sprintf("%s (%d)", p.Key, p.Count))
+		}
+		fmt.Fprintf(out, "Project keys seen in this repository: %s\n", strings.Join(seen, ", "))
+	}
+	opts.Project = strings.ToUpper(ask("Jira project key", opts.Project))
+
+	opts.JiraURL = ask("Jira URL, e.g. https://example.atlassian.net ('-' for none)", opts.JiraURL)
+
+	if opts.CommitPosition == "" {
+		opts.CommitPosition = config.CommitPositionSubject
+	}
+	for {
+		answer := strings.ToLower(ask("Reference tickets in the commit subject or in a trailer (subject/trailer)", opts.CommitPosition))
+		if answer == config.CommitPositionSubject || answer == config.CommitPositionTrailer {
+			opts.CommitPosition = answer
+			break
+		}
+		fmt.Fprintln(out, "Please answer subject or trailer.")
+	}
+
+	if opts.CommitPosition == config.CommitPositionSubject {
+		if opts.CommitFormat == "" {
+			opts.CommitFormat = history.detectCommitFormat()
+		}
+		if opts.CommitFormat == "" {
+			opts.CommitFormat = lib.DefaultCommitFormat
+		}
+		for {
+			answer := ask("Commit subject format ('-' to allow the key anywhere)", opts.CommitFormat)
+			_, err := lib.CommitFormatPattern(answer)
+			if answer == "" || err == nil {
+				opts.CommitFormat = answer
+				break
+			}
+			fmt.Fprintln(out, err)
+		}
+	}
+
+	if opts.BranchPattern == "" {
+		for _, p := range projects {
+			keys.Projects = append(keys.Projects, p.Key)
+		}
+		if opts.Project != "" {
+			keys.Projects = append(keys.Projects, opts.Project)
+		}
+		opts.BranchPattern = history.detectBranchPattern(keys)
+	}
+	for {
+		answer := ask("Branch name pattern, a regular expression ('-' for none)", opts.BranchPattern)
+		if _, err := regexp.Compile(answer); err != nil {
+			fmt.Fprintf(out, "Invalid regular expression: %v\n", err)
+			continue
+		}
+		opts.BranchPattern = answer
+		break
+	}
+
+	if !acceptDefaults {
+		answer, ok := prompt(in, out, "Install jitt's git hooks? [Y/n]: ")
+		installHooks = !ok || answer == "" || strings.HasPrefix(strings.ToLower(answer), "y")
+	}
+	return opts, installHooks
+}
@@ -0,0 +1,373 @@
+package jitt
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bbommarito/jitt/internal/jira"
+	"github.com/bbommarito/jitt/pkg/config"
+	lib "github.com/bbommarito/jitt/pkg/jitt"
+)
+
+// noTicket labels time spent on commits that reference no ticket
+const noTicket = "(no ticket)"
+
+const day = 24 * time.Hour
+
+// workCommit is a commit with the tickets it is attributed to
+type workCommit struct {
+	When time.Time
+	Keys []string
+}
+
+// workEntry is the estimated time spent on one ticket on one day
+type workEntry struct {
+	Key      string
+	Day      time.Time
+	Started  time.Time
+	Duration time.Duration
+}
+
+// HandleWorklog handles the 'jitt worklog' command
+func HandleWorklog(stdio IO, args []string) error {
+	fs := flag.NewFlagSet("worklog", flag.ContinueOnError)
+	fs.SetOutput(stdio.Err)
+	since := fs.String("since", "monday", "start day: today, yesterday, a weekday, Nd or YYYY-MM-DD")
+	until := fs.String("until", "", "last day to include (default: today)")
+	author := fs.String("author", "", "author email to report on (default: git config user.email)")
+	gap := fs.Duration("gap", 0, "longest pause within a work session (default: worklog.session_gap)")
+	submit := fs.Bool("submit", false, "offer to submit the worklogs to Jira")
+	yes := fs.Bool("yes", false, "submit without asking for confirmation")
+	if _, err := parseFlags(fs, args); err != nil {
+		return flagError(err)
+	}
+
+	cfg, err := loadRepoConfig()
+	if err != nil {
+		return err
+	}
+	if *gap > 0 {
+		cfg.Worklog.SessionGap = *gap
+	}
+
+	now := time.Now()
+	from, err := parseDay(*since, now)
+	if err != nil {
+		return usageError("Error: invalid --since: %v", err)
+	}
+	to := now
+	if *until != "" {
+		last, err := parseDay(*until, now)
+		if err != nil {
+			return usageError("Error: invalid --until: %v", err)
+		}
+		to = last.Add(day - time.Second)
+	}
+
+	email := *author
+	if email == "" {
+		email, _ = runGit("config", "user.email")
+	}
+	if email == "" {
+		return usageError("Error: no author email: pass --author or set git config user.email")
+	}
+
+	commits, err := authoredCommits(cfg, email, from, to)
+	if err != nil {
+		return failure("Error reading commits: %v", err)
+	}
+	if len(commits) == 0 {
+		fmt.Fprintf(stdio.Out, "No commits by %s between %s and %s\n", email, from.Format(time.DateOnly), to.Format(time.DateOnly))
+		return nil
+	}
+
+	entries := estimateWork(commits, cfg.Worklog.SessionGap, cfg.Worklog.FirstCommit)
+	printWorklogTable(stdio.Out, entries, from, to)
+
+	if *submit {
+		return submitWorklogs(stdio, cfg, email, entries, *yes)
+	}
+	return nil
+}
+
+// parseDay resolves a day expression to local midnight of that day
+func parseDay(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	switch value {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if value == strings.ToLower(wd.String()) {
+			back := (int(today.Weekday()) - int(wd) + 7) % 7
+			return today.AddDate(0, 0, -back), nil
+		}
+	}
+
+	var n int
+	if _, err := fmt.Sscanf(value, "%dd", &n); err == nil && strings.HasSuffix(value, "d") {
+		return today.AddDate(0, 0, -n), nil
+	}
+
+	t, err := time.ParseInLocation(time.DateOnly, value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not today, yesterday, a weekday, Nd or YYYY-MM-DD", value)
+	}
+	return t, nil
+}
+
+// authoredCommits lists the commits by email on any local branch authored
+// within [from, to], oldest first. Commits without a ticket in their message
+// are attributed to the ticket of the branch they were found on.
+func authoredCommits(cfg *config.Config, email string, from, to time.Time) ([]workCommit, error) {
+	// A fixed string, bracketed as in "Name <email>", matches just that
+	// address: neither part of another one nor as a regular expression.
+	// git log --since and --until go by committer date, which a rebase or
+	// amend moves, so the window is applied to the author date below.
+	out, err := runGit("log", "--branches", "--source", "--reverse", "--date-order",
+		"--fixed-strings", "--author=<"+email+">",
+		"--format=%S%x1f%aI%x1f%B%x00")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []workCommit
+	for _, record := range strings.Split(out, "\x00") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x1f", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		when, err := time.Parse(time.RFC3339, fields[1])
+		if err != nil || when.Before(from) || when.After(to) {
+			continue
+		}
+
+		keys := lib.TicketKeys(cfg, fields[2])
+		if len(keys) == 0 {
+			keys = lib.BranchKeys(cfg, strings.TrimPrefix(fields[0], "refs/heads/"))
+		}
+		commits = append(commits, workCommit{When: when.Local(), Keys: keys})
+	}
+
+	sort.SliceStable(commits, func(i, j int) bool { return commits[i].When.Before(commits[j].When) })
+	return commits, nil
+}
+
+// estimateWork turns commit timestamps into time spent. Commits closer than
+// gap belong to one session, and the time since the previous commit goes to
+// the tickets of the later commit; the first commit of a session is credited
+// with firstCommit. Time is split evenly across a commit's tickets.
+func estimateWork(commits []workCommit, gap, firstCommit time.Duration) []*workEntry {
+	index := make(map[string]*workEntry)
+	var entries []*workEntry
+
+	for i, c := range commits {
+		spent := firstCommit
+		if i > 0 {
+			if since := c.When.Sub(commits[i-1].When); since <= gap {
+				spent = since
+			}
+		}
+
+		keys := c.Keys
+		if len(keys) == 0 {
+			keys = []string{noTicket}
+		}
+		share := spent / time.Duration(len(keys))
+		dayStart := time.Date(c.When.Year(), c.When.Month(), c.When.Day(), 0, 0, 0, 0, c.When.Location())
+
+		for _, key := range keys {
+			id := workID(key, dayStart)
+			entry, ok := index[id]
+			if !ok {
+				entry = &workEntry{Key: key, Day: dayStart, Started: c.When.Add(-share)}
+				index[id] = entry
+				entries = append(entries, entry)
+			}
+			entry.Duration += share
+		}
+	}
+	return entries
+}
+
+// printWorklogTable prints one row per ticket and one column per day
+func printWorklogTable(out io.Writer, entries []*workEntry, from, to time.Time) {
+	var days []time.Time
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		days = append(days, d)
+	}
+
+	perTicket := make(map[string]map[string]time.Duration)
+	var keys []string
+	for _, e := range entries {
+		if perTicket[e.Key] == nil {
+			perTicket[e.Key] = make(map[string]time.Duration)
+			keys = append(keys, e.Key)
+		}
+		perTicket[e.Key][e.Day.Format(time.DateOnly)] += e.Duration
+	}
+	sort.Strings(keys)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	header := []string{"Ticket"}
+	for _, d := range days {
+		header = append(header, d.Format("Mon 01/02"))
+	}
+	fmt.Fprintln(w, strings.Join(append(header, "Total"), "\t"))
+
+	dayTotals := make(map[string]time.Duration)
+	var grandTotal time.Duration
+	for _, key := range keys {
+		row := []string{key}
+		var total time.Duration
+		for _, d := range days {
+			spent := perTicket[key][d.Format(time.DateOnly)]
+			dayTotals[d.Format(time.DateOnly)] += spent
+			total += spent
+			row = append(row, formatWorkDuration(spent))
+		}
+		grandTotal += total
+		fmt.Fprintln(w, strings.Join(append(row, formatWorkDuration(total)), "\t"))
+	}
+
+	row := []string{"Total"}
+	for _, d := range days {
+		row = append(row, formatWorkDuration(dayTotals[d.Format(time.DateOnly)]))
+	}
+	fmt.Fprintln(w, strings.Join(append(row, formatWorkDuration(grandTotal)), "\t"))
+	_ = w.Flush()
+}
+
+// formatWorkDuration renders a duration in Jira notation, rounded to the minute
+func formatWorkDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d <= 0 {
+		return "-"
+	}
+
+	hours := int(d / time.Hour)
+	minutes := int((d % time.Hour) / time.Minute)
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%dh", hours)
+	default:
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	}
+}
+
+// worklogsLog is kept in the git directory: the time submitted per author,
+// ticket and day, as "<email> <key> <day> <seconds>" lines, the latest winning
+const worklogsLog = "jitt/worklogs"
+
+// submitWorklogs logs each ticket's daily time in Jira once the user
+// confirms. Time already submitted for a ticket and day is left out, so
+// running it again only logs what has been added since.
+func submitWorklogs(stdio IO, cfg *config.Config, email string, entries []*workEntry, assumeYes bool) error {
+	logPath, err := runGit("rev-parse", "--git-path", worklogsLog)
+	if err != nil {
+		return failure("Error: %v", err)
+	}
+	submitted := readSubmittedWork(logPath, email)
+
+	var pending []workEntry
+	var total time.Duration
+	skipped := 0
+	for _, e := range entries {
+		if e.Key == noTicket || e.Duration.Round(time.Minute) <= 0 {
+			continue
+		}
+		remaining := *e
+		remaining.Duration = e.Duration.Round(time.Minute) - submitted[workID(e.Key, e.Day)]
+		if remaining.Duration <= 0 {
+			skipped++
+			continue
+		}
+		pending = append(pending, remaining)
+		total += remaining.Duration
+	}
+	if skipped > 0 {
+		fmt.Fprintf(stdio.Out, "Skipping %d worklogs already submitted.\n", skipped)
+	}
+	if len(pending) == 0 {
+		fmt.Fprintln(stdio.Out, "Nothing to submit.")
+		return nil
+	}
+
+	client, err := newJiraClient(cfg)
+	if err != nil {
+		return configError("Error: %v", err)
+	}
+
+	if !assumeYes {
+		answer, _ := prompt(stdio.reader(), stdio.Out,
+			fmt.Sprintf("\nSubmit %d worklogs (%s) to Jira? [y/N] ", len(pending), formatWorkDuration(total)))
+		if a := strings.ToLower(answer); a != "y" && a != "yes" {
+			fmt.Fprintln(stdio.Out, "Nothing submitted.")
+			return nil
+		}
+	}
+
+	// Keep going after a failure, reporting each; the last one sets the exit code
+	var failed error
+	for _, e := range pending {
+		worklog := jira.Worklog{
+			TimeSpentSeconds: int(e.Duration.Seconds()),
+			Started:          e.Started,
+			Comment:          "Logged by jitt worklog",
+		}
+		if err := client.AddWorklog(context.Background(), e.Key, worklog); err != nil {
+			failed = jiraError(err, "Error logging %s on %s: %v", formatWorkDuration(e.Duration), e.Key, err)
+			fmt.Fprintln(stdio.Err, failed)
+			continue
+		}
+		fmt.Fprintf(stdio.Out, "Logged %s on %s (%s)\n", formatWorkDuration(e.Duration), e.Key, e.Day.Format("Mon 01/02"))
+
+		seconds := int((submitted[workID(e.Key, e.Day)] + e.Duration).Seconds())
+		line := fmt.Sprintf("%s %s %s %d", email, e.Key, e.Day.Format(time.DateOnly), seconds)
+		if err := appendLines(logPath, []string{line}); err != nil {
+			failed = failure("Error recording the worklog on %s: %v", e.Key, err)
+			fmt.Fprintln(stdio.Err, failed)
+		}
+	}
+
+	if failed != nil {
+		return &Error{Code: ExitCode(failed)}
+	}
+	return nil
+}
+
+// workID identifies the time logged on a ticket on a day
+func workID(key string, day time.Time) string {
+	return key + "@" + day.Format(time.DateOnly)
+}
+
+// readSubmittedWork returns the time submitted by email per ticket and day
+func readSubmittedWork(path, email string) map[string]time.Duration {
+	submitted := make(map[string]time.Duration)
+	for _, line := range readLineList(path) {
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[0] != email {
+			continue
+		}
+		day, err := time.ParseInLocation(time.DateOnly, fields[2], time.Local)
+		var seconds int
+		if _, scanErr := fmt.Sscan(fields[3], &seconds); err != nil || scanErr != nil {
+			continue
+		}
+		submitted[workID(fields[1], day)] = time.Duration(seconds) * time.Second
+	}
+	return submitted
+}
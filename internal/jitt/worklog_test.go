@@ -0,0 +1,174 @@
+package jitt
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
+)
+
+func runWorklogCommand(stdin string, args ...string) *gexec.Session {
+	command := exec.Command(pathToJittBinary, append([]string{"worklog"}, args...)...)
+	command.Env = append(os.Environ(), "TZ=UTC")
+	command.Stdin = strings.NewReader(stdin)
+	session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+	Expect(err).NotTo(HaveOccurred())
+	return session
+}
+
+var _ = Describe("jitt worklog command", func() {
+	var (
+		tmpDir string
+		oldCwd string
+		fake   *fakeJira
+		week   = []string{"--since", "2026-10-12", "--until", "2026-10-18"}
+	)
+
+	BeforeEach(func() {
+		tmpDir = GinkgoT().TempDir()
+
+		var err error
+		oldCwd, err = os.Getwd()
+		Expect(err).To(Succeed())
+		Expect(os.Chdir(tmpDir)).To(Succeed())
+
+		initGitRepo()
+		fake = newFakeJira()
+		for _, key := range []string{"ABC-1", "ABC-2", "ABC-3"} {
+			fake.addIssue(key, "In Progress")
+		}
+		Expect(fake.writeConfig("")).To(Succeed())
+
+		commitAt("ABC-1 start login", "2026-10-12T09:00:00Z")
+		commitAt("ABC-1 validate form", "2026-10-12T10:00:00Z")
+		commitAt("ABC-2 after lunch", "2026-10-12T13:00:00Z")
+		git("checkout", "-q", "-b", "ABC-3-reports")
+		commitAt("wip", "2026-10-12T13:45:00Z")
+		git("checkout", "-q", "main")
+		commitAt("ABC-1 fix typo", "2026-10-13T09:00:00Z")
+	})
+
+	AfterEach(func() {
+		fake.Close()
+		Expect(os.Chdir(oldCwd)).To(Succeed())
+	})
+
+	It("should estimate time per ticket and day from commit sessions", func() {
+		session := runWorklogCommand("", week...)
+
+		Eventually(session).Should(gexec.Exit(0))
+		output := string(session.Out.Contents())
+		Expect(output).To(ContainSubstring("Mon 10/12"))
+		Expect(output).To(ContainSubstring("Sun 10/18"))
+		Expect(output).To(MatchRegexp(`ABC-1\s+1h 30m\s+30m\s+-\s+-\s+-\s+-\s+-\s+2h\n`))
+		Expect(output).To(MatchRegexp(`ABC-2\s+30m\s+-.*\s30m\n`))
+		Expect(output).To(MatchRegexp(`ABC-3\s+45m\s+-.*\s45m\n`))
+		Expect(output).To(MatchRegexp(`Total\s+2h 45m\s+30m.*\s3h 15m\n`))
+	})
+
+	It("should select commits by author date, as after a rebase", func() {
+		commitDated := func(message, authored, committed string) {
+			cmd := exec.Command("git", "commit", "-q", "--allow-empty", "-m", message)
+			cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+authored, "GIT_COMMITTER_DATE="+committed)
+			out, err := cmd.CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), "git commit: %s", out)
+		}
+		commitDated("ABC-2 rebased later", "2026-10-14T09:00:00Z", "2026-10-20T09:00:00Z")
+		commitDated("ABC-3 authored last week", "2026-10-09T09:00:00Z", "2026-10-14T10:00:00Z")
+
+		session := runWorklogCommand("", week...)
+
+		Eventually(session).Should(gexec.Exit(0))
+		output := string(session.Out.Contents())
+		Expect(output).To(MatchRegexp(`ABC-2\s+30m\s+-\s+30m\s+-.*\s1h\n`))
+		Expect(output).To(MatchRegexp(`ABC-3\s+45m\s+-.*\s45m\n`))
+	})
+
+	It("should honor a custom session gap", func() {
+		session := runWorklogCommand("", append(week, "--gap", "4h")...)
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(MatchRegexp(`ABC-2\s+3h\s`))
+	})
+
+	It("should report when there are no commits in range", func() {
+		session := runWorklogCommand("", "--since", "2026-09-01", "--until", "2026-09-07")
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("No commits by test@example.com"))
+	})
+
+	It("should only count the given author", func() {
+		session := runWorklogCommand("", append(week, "--author", "someone@else.com")...)
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("No commits by someone@else.com"))
+	})
+
+	It("should match the author's email literally and in full", func() {
+		for _, author := range []string{"test@example.c.m", "est@example.com", ".*"} {
+			session := runWorklogCommand("", append(week, "--author", author)...)
+
+			Eventually(session).Should(gexec.Exit(0))
+			Expect(string(session.Out.Contents())).To(ContainSubstring("No commits by " + author))
+		}
+	})
+
+	It("should fail without an author email", func() {
+		git("config", "--unset", "user.email")
+		command := exec.Command(pathToJittBinary, append([]string{"worklog"}, week...)...)
+		command.Env = append(os.Environ(), "HOME="+tmpDir, "XDG_CONFIG_HOME="+tmpDir, "GIT_CONFIG_NOSYSTEM=1")
+		session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+		Expect(err).NotTo(HaveOccurred())
+
+		Eventually(session).Should(gexec.Exit(ExitUsage))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("no author email"))
+	})
+
+	It("should reject unknown day expressions", func() {
+		session := runWorklogCommand("", "--since", "someday")
+
+		Eventually(session).Should(gexec.Exit(ExitUsage))
+		Expect(string(session.Err.Contents())).To(ContainSubstring("invalid --since"))
+	})
+
+	It("should submit worklogs to Jira after confirmation", func() {
+		session := runWorklogCommand("y\n", append(week, "--submit")...)
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("Submit 4 worklogs (3h 15m) to Jira? [y/N]"))
+		Expect(fake.Worklogs()).To(HaveLen(4))
+		Expect(fake.Worklogs()[0]).To(HaveKeyWithValue("key", "ABC-1"))
+		Expect(fake.Worklogs()[0]).To(HaveKeyWithValue("timeSpentSeconds", BeNumerically("==", 5400)))
+		Expect(fake.Worklogs()[0]).To(HaveKeyWithValue("started", "2026-10-12T08:30:00.000+0000"))
+	})
+
+	It("should only submit time that hasn't been submitted yet", func() {
+		Eventually(runWorklogCommand("", append(week, "--submit", "--yes")...)).Should(gexec.Exit(0))
+		Expect(fake.Worklogs()).To(HaveLen(4))
+
+		session := runWorklogCommand("", append(week, "--submit", "--yes")...)
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("Skipping 4 worklogs already submitted."))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("Nothing to submit."))
+		Expect(fake.Worklogs()).To(HaveLen(4))
+
+		commitAt("ABC-1 more validation", "2026-10-13T09:20:00Z")
+		session = runWorklogCommand("", append(week, "--submit", "--yes")...)
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("Logged 20m on ABC-1 (Tue 10/13)"))
+		Expect(fake.Worklogs()).To(HaveLen(5))
+		Expect(fake.Worklogs()[4]).To(HaveKeyWithValue("timeSpentSeconds", BeNumerically("==", 1200)))
+	})
+
+	It("should not submit anything when the user declines", func() {
+		session := runWorklogCommand("n\n", append(week, "--submit")...)
+
+		Eventually(session).Should(gexec.Exit(0))
+		Expect(string(session.Out.Contents())).To(ContainSubstring("Nothing submitted."))
+		Expect(fake.Worklogs()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,430 @@
+// Package config reads .jitt.yaml files into a Config: the defaults, the
+// nested files of a repository and the base configs they extend. It is part
+// of jitt's public API, as package jitt exposes its types under the same
+// names, so its exported identifiers follow the same compatibility promise.
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// Config represents the application configuration
+type Config struct {
+	Jira         JiraConfig         `mapstructure:"jira"`
+	Commit       CommitConfig       `mapstructure:"commit"`
+	Branch       BranchConfig       `mapstructure:"branch"`
+	Workflow     WorkflowConfig     `mapstructure:"workflow"`
+	SmartCommits SmartCommitsConfig `mapstructure:"smart_commits"`
+	Worklog      WorklogConfig      `mapstructure:"worklog"`
+	PR           PRConfig           `mapstructure:"pr"`
+	Lint         LintConfig         `mapstructure:"lint"`
+	Projects     []ProjectMapping   `mapstructure:"projects"`
+	Keys         KeysConfig         `mapstructure:"keys"`
+	Validate     ValidateConfig     `mapstructure:"validate"`
+	Bypass       BypassConfig       `mapstructure:"bypass"`
+	Extends      ExtendsConfig      `mapstructure:"extends"`
+}
+
+// JiraConfig represents Jira-specific configuration
+type JiraConfig struct {
+	Project string `mapstructure:"project"`
+	URL     string `mapstructure:"url"`
+	// AcceptanceCriteriaField is the custom field (e.g. customfield_10035)
+	// holding acceptance criteria, if the instance uses one
+	AcceptanceCriteriaField string `mapstructure:"acceptance_criteria_field"`
+}
+
+// Ticket key positions in commit messages
+const (
+	CommitPositionSubject = "subject"
+	CommitPositionTrailer = "trailer"
+)
+
+// CommitConfig describes how ticket keys are written in commit messages
+type CommitConfig struct {
+	// Position is subject (keys anywhere, usually in the subject) or trailer
+	// (keys in a trailer such as "Refs: ABC-123" at the end of the message)
+	Position string `mapstructure:"position"`
+	// Trailer is the trailer token used in trailer mode, e.g. Refs or Jira
+	Trailer string `mapstructure:"trailer"`
+	// Format is the canonical subject layout, with {key} and {message}
+	// placeholders (e.g. "[{key}] {message}"). When set, 'jitt validate'
+	// requires subjects to follow it and 'jitt validate --fix' rewrites them.
+	Format string `mapstructure:"format"`
+}
+
+// BranchConfig constrains branch names
+type BranchConfig struct {
+	// Pattern is a regular expression branch names must match, e.g.
+	// ^(feature|bugfix)/[A-Z]+-[0-9]+-; empty allows any name
+	Pattern string `mapstructure:"pattern"`
+	// Exempt lists branch globs (e.g. release/*) not held to the pattern;
+	// workflow.main_branch is always exempt
+	Exempt []string `mapstructure:"exempt"`
+}
+
+// ProjectMapping assigns the files matching Paths (globs relative to the
+// repository root, where ** spans directories) to the Jira projects in Keys
+type ProjectMapping struct {
+	Paths []string `mapstructure:"paths"`
+	Keys  []string `mapstructure:"keys"`
+}
+
+// KeysConfig tunes which words count as ticket keys
+type KeysConfig struct {
+	// Allow lists project keys recognised besides jira.project and the
+	// projects mappings, e.g. another team's project commits may mention
+	Allow []string `mapstructure:"allow"`
+	// Deny lists prefixes never taken for project keys, on top of the
+	// built-in UTF, SHA, ISO and the like
+	Deny []string `mapstructure:"deny"`
+	// Min and Max bound issue numbers; zero leaves that side open
+	Min int `mapstructure:"min"`
+	Max int `mapstructure:"max"`
+}
+
+// ValidateConfig holds settings for commit message validation
+type ValidateConfig struct {
+	Exempt ExemptConfig `mapstructure:"exempt"`
+}
+
+// ExemptConfig lists commits whose messages aren't validated, such as those
+// made by bots or touching only documentation
+type ExemptConfig struct {
+	// Authors and Committers are email globs, where * matches anything (e.g.
+	// *[bot]@users.noreply.github.com)
+	Authors    []string `mapstructure:"authors"`
+	Committers []string `mapstructure:"committers"`
+	// Paths exempts commits whose files all match these patterns (e.g. docs/**, *.md)
+	Paths []string `mapstructure:"paths"`
+	// Merges and Reverts exempt merge commits and commits made by git revert
+	Merges  bool `mapstructure:"merges"`
+	Reverts bool `mapstructure:"reverts"`
+}
+
+// BypassConfig controls skipping jitt's checks with JITT_SKIP=reason or a
+// [skip-jitt: reason] marker, which records the reason in a trailer
+type BypassConfig struct {
+	// Enabled allows bypasses at all (default true)
+	Enabled bool `mapstructure:"enabled"`
+	// DisabledBranches lists branch globs (e.g. release/*) where commits
+	// can't skip the checks
+	DisabledBranches []string `mapstructure:"disabled_branches"`
+}
+
+// KnownProjects returns every project key the configuration mentions
+func (c *Config) KnownProjects() []string {
+	var projects []string
+	seen := make(map[string]bool)
+	add := func(key string) {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			projects = append(projects, key)
+		}
+	}
+
+	add(c.Jira.Project)
+	for _, m := range c.Projects {
+		for _, key := range m.Keys {
+			add(key)
+		}
+	}
+	for _, key := range c.Keys.Allow {
+		add(key)
+	}
+	return projects
+}
+
+// WorkflowConfig maps git events to the Jira transition (or target status)
+// that should be applied to the tickets involved. Empty events are ignored.
+type WorkflowConfig struct {
+	BranchStarted string `mapstructure:"branch_started"`
+	FirstPush     string `mapstructure:"first_push"`
+	Merged        string `mapstructure:"merged"`
+	TagCreated    string `mapstructure:"tag_created"`
+	MainBranch    string `mapstructure:"main_branch"`
+	DryRun        bool   `mapstructure:"dry_run"`
+}
+
+// Smart-commit modes
+const (
+	SmartCommitsOff      = "off"
+	SmartCommitsValidate = "validate"
+	SmartCommitsExecute  = "execute"
+)
+
+// SmartCommitsConfig controls handling of Jira smart-commit commands
+// (`ABC-123 #comment ... #time 1h #resolve`) in commit messages
+type SmartCommitsConfig struct {
+	// Mode is off, validate (check commands in 'jitt validate') or execute
+	// (also apply them through the Jira API when pushing)
+	Mode string `mapstructure:"mode"`
+	// Transitions lists the transition commands accepted when Jira can't be asked
+	Transitions []string `mapstructure:"transitions"`
+}
+
+// WorklogConfig tunes how 'jitt worklog' turns commit timestamps into time spent
+type WorklogConfig struct {
+	// SessionGap is the longest pause between two commits of the same work session
+	SessionGap time.Duration `mapstructure:"session_gap"`
+	// FirstCommit is the time credited for the work before a session's first commit
+	FirstCommit time.Duration `mapstructure:"first_commit"`
+}
+
+// PRConfig configures 'jitt pr describe'
+type PRConfig struct {
+	// Template is the path of a text/template file used instead of the built-in one
+	Template string `mapstructure:"template"`
+	// ExcerptLength caps the number of description characters included
+	ExcerptLength int `mapstructure:"excerpt_length"`
+}
+
+// Lint rule severities. Rules are off unless given a severity; warnings are
+// reported without failing validation.
+const (
+	SeverityOff     = "off"
+	SeverityWarning = "warning"
+	SeverityError   = "error"
+)
+
+// LintConfig holds the commit-message style rules checked by 'jitt validate'
+// in addition to the ticket reference
+type LintConfig struct {
+	// Conventional requires a Conventional Commits subject: type(scope): description
+	Conventional ConventionalRule `mapstructure:"conventional"`
+	// SubjectLength limits the length of the subject line
+	SubjectLength LengthRule `mapstructure:"subject_length"`
+	// ImperativeMood flags subjects starting with "added", "fixes", "updating"...
+	ImperativeMood LintRule `mapstructure:"imperative_mood"`
+	// SubjectPeriod forbids a trailing period on the subject line
+	SubjectPeriod LintRule `mapstructure:"subject_period"`
+	// BlankLine requires a blank line between the subject and the body
+	BlankLine LintRule `mapstructure:"blank_line"`
+	// BodyWrap limits the length of body lines
+	BodyWrap LengthRule `mapstructure:"body_wrap"`
+	// ForbiddenWords rejects words or phrases (case-insensitive) such as "wip"
+	ForbiddenWords WordsRule `mapstructure:"forbidden_words"`
+	// RequiredTrailers lists trailers (e.g. Signed-off-by) every message must end with
+	RequiredTrailers WordsRule `mapstructure:"required_trailers"`
+}
+
+// LintRule is a rule that only needs a severity
+type LintRule struct {
+	Severity string `mapstructure:"severity"`
+}
+
+// ConventionalRule restricts Conventional Commits types and, when listed, scopes
+type ConventionalRule struct {
+	Severity string   `mapstructure:"severity"`
+	Types    []string `mapstructure:"types"`
+	Scopes   []string `mapstructure:"scopes"`
+}
+
+// LengthRule is a rule with a maximum line length in characters
+type LengthRule struct {
+	Severity string `mapstructure:"severity"`
+	Max      int    `mapstructure:"max"`
+}
+
+// WordsRule is a rule with a list of words, phrases or trailer names
+type WordsRule struct {
+	Severity string   `mapstructure:"severity"`
+	Words    []string `mapstructure:"words"`
+}
+
+// Load loads configuration from .jitt.yaml file
+func Load() (*Config, error) {
+	v, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+
+	return &config, nil
+}
+
+// load reads .jitt.yaml in the current directory over the defaults and the
+// base it extends. Every call gets its own viper instance, so loads don't
+// share state with each other or with callers using viper themselves.
+func load() (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigName(".jitt")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(".")
+
+	setDefaults(v)
+
+	if err := v.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+	if err := mergeUnderBase(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Defaults returns the configuration used when .jitt.yaml sets nothing. It
+// must agree with setDefaults.
+func Defaults() *Config {
+	return &Config{
+		Commit:       CommitConfig{Position: CommitPositionSubject, Trailer: "Refs"},
+		Workflow:     WorkflowConfig{MainBranch: "main"},
+		Bypass:       BypassConfig{Enabled: true},
+		SmartCommits: SmartCommitsConfig{Mode: SmartCommitsValidate},
+		Worklog:      WorklogConfig{SessionGap: 2 * time.Hour, FirstCommit: 30 * time.Minute},
+		PR:           PRConfig{ExcerptLength: 500},
+		Lint: LintConfig{
+			Conventional: ConventionalRule{Types: []string{
+				"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert",
+			}},
+			SubjectLength: LengthRule{Max: 72},
+			BodyWrap:      LengthRule{Max: 72},
+		},
+	}
+}
+
+// Parse reads a .jitt.yaml document over the defaults. Its extends section is
+// kept but not followed, since a reader has no directory to resolve it from.
+func Parse(r io.Reader) (*Config, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	setDefaults(v)
+	if err := v.ReadConfig(r); err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// setDefaults registers the default value of every setting on v; Defaults
+// returns the same values as a Config
+func setDefaults(v *viper.Viper) {
+	v.SetDefault("jira.project", "")
+	v.SetDefault("commit.position", CommitPositionSubject)
+	v.SetDefault("commit.trailer", "Refs")
+	v.SetDefault("workflow.main_branch", "main")
+	v.SetDefault("bypass.enabled", true)
+	v.SetDefault("smart_commits.mode", SmartCommitsValidate)
+	v.SetDefault("worklog.session_gap", "2h")
+	v.SetDefault("worklog.first_commit", "30m")
+	v.SetDefault("pr.excerpt_length", 500)
+	v.SetDefault("lint.conventional.types", []string{
+		"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert",
+	})
+	v.SetDefault("lint.subject_length.max", 72)
+	v.SetDefault("lint.body_wrap.max", 72)
+}
+
+// Settings returns the effective settings of .jitt.yaml in the current
+// directory, defaults included, as nested maps keyed like .jitt.yaml
+func Settings() (map[string]any, error) {
+	v, err := load()
+	if err != nil {
+		return nil, err
+	}
+	return v.AllSettings(), nil
+}
+
+// Exists checks if the config file exists
+func Exists() bool {
+	_, err := os.Stat(".jitt.yaml")
+	return err == nil
+}
+
+// Create creates a new config file with the given project
+func Create(project string) error {
+	return CreateWithOptions(InitOptions{Project: project})
+}
+
+// CreateWithProjects creates a new config file with the given project and
+// path-to-project mappings
+func CreateWithProjects(project string, projects []ProjectMapping) error {
+	return CreateWithOptions(InitOptions{Project: project, Projects: projects})
+}
+
+// InitOptions are the answers 'jitt init' writes to a new config file. Empty
+// values are left out so their defaults apply.
+type InitOptions struct {
+	Project        string
+	JiraURL        string
+	CommitPosition string
+	CommitFormat   string
+	BranchPattern  string
+	Projects       []ProjectMapping
+	Extends        ExtendsConfig
+}
+
+// CreateWithOptions creates a new config file from the answers to 'jitt init'
+func CreateWithOptions(opts InitOptions) error {
+	v := viper.New()
+	v.SetConfigType("yaml")
+
+	// Leave the project to the base config unless one was given
+	if opts.Project != "" || opts.Extends.Source == "" {
+		v.Set("jira.project", opts.Project)
+	}
+
+	for key, value := range map[string]string{
+		"extends.source":  opts.Extends.Source,
+		"extends.ref":     opts.Extends.Ref,
+		"extends.path":    opts.Extends.Path,
+		"jira.url":        opts.JiraURL,
+		"commit.position": opts.CommitPosition,
+		"commit.format":   opts.CommitFormat,
+		"branch.pattern":  opts.BranchPattern,
+	} {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+
+	if len(opts.Projects) > 0 {
+		entries := make([]map[string]any, 0, len(opts.Projects))
+		for _, m := range opts.Projects {
+			entries = append(entries, map[string]any{"paths": m.Paths, "keys": m.Keys})
+		}
+		v.Set("projects", entries)
+	}
+
+	return v.WriteConfigAs(".jitt.yaml")
+}
+
+// Update updates an existing config file with new values
+func Update(key, value string) error {
+	if !Exists() {
+		return fmt.Errorf("config file not found - run 'jitt init' first")
+	}
+
+	// Load existing config first, without defaults or the base it extends,
+	// so only what the file already sets is written back
+	v := viper.New()
+	v.SetConfigName(".jitt")
+	v.SetConfigType("yaml")
+	v.AddConfigPath(".")
+
+	if err := v.ReadInConfig(); err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	// Set the new value
+	v.Set(key, value)
+
+	// Write back to file
+	return v.WriteConfig()
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -26,14 +27,10 @@ var _ = Describe("Config package", func() {
 		oldCwd, err = os.Getwd()
 		Expect(err).To(Succeed())
 		Expect(os.Chdir(tmpDir)).To(Succeed())
-
-		// Reset viper state to avoid interference between tests
-		viper.Reset()
 	})
 
 	AfterEach(func() {
 		Expect(os.Chdir(oldCwd)).To(Succeed())
-		viper.Reset()
 	})
 
 	Describe("Exists", func() {
@@ -80,6 +77,22 @@ var _ = Describe("Config package", func() {
 		})
 	})
 
+	Describe("CreateWithProjects", func() {
+		It("should write path mappings that load back", func() {
+			err := CreateWithProjects("ABC", []ProjectMapping{
+				{Paths: []string{"services/billing/**"}, Keys: []string{"BILL"}},
+				{Paths: []string{"web/**", "*.css"}, Keys: []string{"WEB", "UI"}},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg, err := Load()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Projects).To(HaveLen(2))
+			Expect(cfg.Projects[1].Paths).To(Equal([]string{"web/**", "*.css"}))
+			Expect(cfg.KnownProjects()).To(Equal([]string{"ABC", "BILL", "WEB", "UI"}))
+		})
+	})
+
 	Describe("Load", func() {
 		Context("when config file does not exist", func() {
 			It("should return config file not found error", func() {
@@ -131,6 +144,40 @@ var _ = Describe("Config package", func() {
 			})
 		})
 
+		Context("when workflow and smart commit settings are omitted", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: VALIDPROJ"), 0o600)).To(Succeed())
+			})
+
+			It("should default the main branch and validate smart commits", func() {
+				cfg, err := Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.Workflow.MainBranch).To(Equal("main"))
+				Expect(cfg.Workflow.DryRun).To(BeFalse())
+				Expect(cfg.SmartCommits.Mode).To(Equal(SmartCommitsValidate))
+				Expect(cfg.Worklog.SessionGap).To(Equal(2 * time.Hour))
+				Expect(cfg.Worklog.FirstCommit).To(Equal(30 * time.Minute))
+			})
+		})
+
+		Context("when workflow rules are configured", func() {
+			BeforeEach(func() {
+				Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  url: https://example.atlassian.net\n"+
+					"workflow:\n  first_push: In Review\n  main_branch: trunk\n"+
+					"smart_commits:\n  mode: execute\n  transitions: [resolve]\n"), 0o600)).To(Succeed())
+			})
+
+			It("should load them", func() {
+				cfg, err := Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.Jira.URL).To(Equal("https://example.atlassian.net"))
+				Expect(cfg.Workflow.FirstPush).To(Equal("In Review"))
+				Expect(cfg.Workflow.MainBranch).To(Equal("trunk"))
+				Expect(cfg.SmartCommits.Mode).To(Equal(SmartCommitsExecute))
+				Expect(cfg.SmartCommits.Transitions).To(ConsistOf("resolve"))
+			})
+		})
+
 		Context("when config file is empty", func() {
 			BeforeEach(func() {
 				Expect(os.WriteFile(".jitt.yaml", []byte(""), 0o600)).To(Succeed())
@@ -140,9 +187,41 @@ var _ = Describe("Config package", func() {
 				cfg, err := Load()
 				Expect(err).NotTo(HaveOccurred())
 				Expect(cfg).NotTo(BeNil())
+				Expect(cfg).To(Equal(Defaults()))
+			})
+		})
+
+		Context("when the process uses viper itself", func() {
+			BeforeEach(func() {
+				viper.Reset()
+				viper.Set("jira.project", "CALLER")
+			})
+
+			AfterEach(func() {
+				viper.Reset()
+			})
+
+			It("should neither read nor change the global instance", func() {
+				Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  url: https://jira.example.com\n"), 0o600)).To(Succeed())
+
+				cfg, err := Load()
+				Expect(err).NotTo(HaveOccurred())
 				Expect(cfg.Jira.Project).To(Equal(""))
+				Expect(viper.AllSettings()).To(Equal(map[string]any{"jira": map[string]any{"project": "CALLER"}}))
 			})
 		})
+
+		It("should not leak settings from one load into the next", func() {
+			Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  project: FIRST\n"), 0o600)).To(Succeed())
+			_, err := Load()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.Chdir(GinkgoT().TempDir())).To(Succeed())
+			Expect(os.WriteFile(".jitt.yaml", []byte("jira:\n  url: https://jira.example.com\n"), 0o600)).To(Succeed())
+			cfg, err := Load()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Jira.Project).To(Equal(""))
+		})
 	})
 
 	Describe("Update", func() {
@@ -168,6 +247,16 @@ var _ = Describe("Config package", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(string(content)).To(ContainSubstring("project: NEWPROJ"))
 			})
+
+			It("should not write the defaults into the file", func() {
+				_, err := Load()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(Update("jira.project", "NEWPROJ")).To(Succeed())
+
+				content, err := os.ReadFile(".jitt.yaml")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(content)).NotTo(ContainSubstring("main_branch"))
+			})
 		})
 
 		Context("when config file exists but is unreadable", func() {
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// VendorDir holds, next to a .jitt.yaml, the vendored copy of a base config
+// taken from a git repository
+const VendorDir = ".jitt"
+
+// BaseFileName is the name of the vendored base config inside VendorDir
+const BaseFileName = "base.yaml"
+
+// DefaultBasePath is the file read from a git repository when extends.path is not set
+const DefaultBasePath = FileName
+
+// ExtendsConfig names a shared base configuration that a .jitt.yaml builds
+// on. Its settings are merged under the file's own, which override them.
+type ExtendsConfig struct {
+	// Source is a local file (relative to the .jitt.yaml) or a git repository URL
+	Source string `mapstructure:"source"`
+	// Ref pins the branch, tag or commit of a git source (default HEAD)
+	Ref string `mapstructure:"ref"`
+	// Path is the file within a git source (default .jitt.yaml)
+	Path string `mapstructure:"path"`
+}
+
+// IsGit reports whether Source is a git repository rather than a local file.
+// Git sources are read from the vendored copy 'jitt config update-base' keeps.
+func (e ExtendsConfig) IsGit() bool {
+	s := e.Source
+	return strings.Contains(s, "://") || strings.HasPrefix(s, "git@") || strings.HasSuffix(s, ".git")
+}
+
+// BaseFile returns the file holding the base config for a .jitt.yaml in dir:
+// the vendored copy for git sources, the source itself otherwise
+func (e ExtendsConfig) BaseFile(dir string) string {
+	if e.IsGit() {
+		return filepath.Join(dir, VendorDir, BaseFileName)
+	}
+	if filepath.IsAbs(e.Source) {
+		return e.Source
+	}
+	return filepath.Join(dir, filepath.FromSlash(e.Source))
+}
+
+// ReadExtends reads the extends section of the .jitt.yaml in dir, without
+// loading anything else
+func ReadExtends(dir string) (ExtendsConfig, error) {
+	layer, err := readLayer(dirTree(dir), FileName)
+	if err != nil {
+		return ExtendsConfig{}, err
+	}
+	return layerExtends(layer), nil
+}
+
+func layerExtends(layer *viper.Viper) ExtendsConfig {
+	return ExtendsConfig{
+		Source: layer.GetString("extends.source"),
+		Ref:    layer.GetString("extends.ref"),
+		Path:   layer.GetString("extends.path"),
+	}
+}
+
+// readBase returns the contents of the base config a layer in dir (relative
+// to t) extends, or nil when it extends nothing. The base's own extends
+// section is ignored.
+func readBase(t tree, dir string, layer *viper.Viper) ([]byte, error) {
+	ext := layerExtends(layer)
+	if ext.Source == "" {
+		return nil, nil
+	}
+
+	var data []byte
+	var err error
+	switch {
+	case ext.IsGit():
+		data, err = t.readFile(path.Join(dir, VendorDir, BaseFileName))
+	case filepath.IsAbs(ext.Source):
+		// Only a config on disk may point at another file on disk; one read
+		// from a revision or a pushed tree would otherwise depend on
+		// whatever happens to be at that path where jitt runs
+		if _, ok := t.(dirTree); !ok {
+			return nil, fmt.Errorf("base config %s is outside the configuration tree", ext.Source)
+		}
+		data, err = os.ReadFile(ext.Source)
+	default:
+		data, err = t.readFile(path.Join(dir, filepath.ToSlash(ext.Source)))
+	}
+	if errors.Is(err, fs.ErrNotExist) && ext.IsGit() {
+		return nil, fmt.Errorf("base config from %s has not been vendored - run 'jitt config update-base'", ext.Source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading base config %s: %w", ext.Source, err)
+	}
+	return data, nil
+}
+
+// mergeBase merges the base config a layer in dir extends into v, to be
+// overridden by the layer itself
+func mergeBase(v *viper.Viper, t tree, dir string, layer *viper.Viper) error {
+	data, err := readBase(t, dir, layer)
+	if err != nil || data == nil {
+		return err
+	}
+	if err := v.MergeConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("error reading base config %s: %w", layerExtends(layer).Source, err)
+	}
+	return nil
+}
+
+// mergeUnderBase puts the base config that v's file extends beneath it
+func mergeUnderBase(v *viper.Viper) error {
+	file := v.ConfigFileUsed()
+	source := layerExtends(v).Source
+	data, err := readBase(dirTree(filepath.Dir(file)), ".", v)
+	if err != nil || data == nil {
+		return err
+	}
+
+	local, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return fmt.Errorf("error reading base config %s: %w", source, err)
+	}
+	return v.MergeConfig(bytes.NewReader(local))
+}
+
+// CheckBase makes sure data is a readable base config
+func CheckBase(data []byte) error {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return err
+	}
+	var cfg Config
+	return v.Unmarshal(&cfg)
+}
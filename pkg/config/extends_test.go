@@ -0,0 +1,67 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Extending a base configuration", func() {
+	var root string
+
+	write := func(rel, content string) {
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		Expect(os.MkdirAll(filepath.Dir(full), 0o755)).To(Succeed())
+		Expect(os.WriteFile(full, []byte(content), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		write("shared/base.yaml", "jira:\n  project: ORG\n  url: https://jira.example.com\n"+
+			"lint:\n  subject_period:\n    severity: error\n")
+		write("repo/.jitt.yaml", "extends:\n  source: ../shared/base.yaml\njira:\n  project: ABC\n")
+	})
+
+	It("should merge the base under the file's own settings", func() {
+		cfg, _, err := LoadFor(filepath.Join(root, "repo"), "README.md")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Jira.Project).To(Equal("ABC"))
+		Expect(cfg.Jira.URL).To(Equal("https://jira.example.com"))
+		Expect(cfg.Lint.SubjectPeriod.Severity).To(Equal(SeverityError))
+		Expect(cfg.Extends.Source).To(Equal("../shared/base.yaml"))
+	})
+
+	It("should explain which settings come from the base", func() {
+		settings, _, err := Explain(filepath.Join(root, "repo"), ".")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(settings).To(ContainElements(
+			Setting{Key: "jira.project", Value: "ABC", Source: ".jitt.yaml"},
+			Setting{Key: "jira.url", Value: "https://jira.example.com", Source: ".jitt.yaml (extends ../shared/base.yaml)"},
+		))
+	})
+
+	It("should read git sources from the vendored copy", func() {
+		write("repo/.jitt.yaml", "extends:\n  source: https://git.example.com/org/jitt-config.git\n  ref: v1\n")
+
+		_, _, err := LoadFor(filepath.Join(root, "repo"), ".")
+		Expect(err).To(MatchError(ContainSubstring("has not been vendored - run 'jitt config update-base'")))
+
+		write("repo/.jitt/base.yaml", "jira:\n  project: VEND\n")
+		cfg, _, err := LoadFor(filepath.Join(root, "repo"), ".")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Jira.Project).To(Equal("VEND"))
+	})
+
+	DescribeTable("telling git sources from files",
+		func(source string, git bool) {
+			Expect(ExtendsConfig{Source: source}.IsGit()).To(Equal(git))
+		},
+		Entry("https URL", "https://github.com/org/config", true),
+		Entry("ssh URL", "git@github.com:org/config.git", true),
+		Entry("local bare repository", "../config.git", true),
+		Entry("relative file", "../shared/jitt.yaml", false),
+		Entry("absolute file", "/etc/jitt/base.yaml", false),
+	)
+})
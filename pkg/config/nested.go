@@ -0,0 +1,290 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// FileName is the name of jitt's configuration file, at the repository root
+// and optionally in any subdirectory
+const FileName = ".jitt.yaml"
+
+// ErrNotFound is returned when no .jitt.yaml applies
+var ErrNotFound = errors.New("config file not found")
+
+// Scope is a group of repository paths that share the same effective
+// configuration
+type Scope struct {
+	// Files are the configuration files applied, outermost first
+	Files []string
+	// Paths are the repository paths (slash-separated, relative to the root) in the scope
+	Paths  []string
+	Config *Config
+}
+
+// Setting is one effective configuration value and where it came from
+type Setting struct {
+	Key    string
+	Value  any
+	Source string
+}
+
+// LoadFor loads the configuration applying to a repository path (relative to
+// root): the root .jitt.yaml merged with every .jitt.yaml found in the
+// directories leading to it, like .editorconfig. Nested files override their
+// parents key by key; maps are merged and lists replaced. A file containing
+// `root: true` ignores the files above it.
+func LoadFor(root, relPath string) (*Config, []string, error) {
+	return loadFor(dirTree(root), relPath)
+}
+
+// LoadForFS loads the configuration applying to a path like LoadFor, reading
+// the files from fsys, such as the tree of a git revision. Local extends
+// sources must lie inside fsys.
+func LoadForFS(fsys fs.FS, relPath string) (*Config, []string, error) {
+	return loadFor(fsTree{fsys}, relPath)
+}
+
+func loadFor(t tree, relPath string) (*Config, []string, error) {
+	files, err := layeredFiles(t, relPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v, err := mergeLayers(t, files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var cfg Config
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, nil, fmt.Errorf("error unmarshaling config: %w", err)
+	}
+	return &cfg, files, nil
+}
+
+// Resolve groups paths by the configuration applying to them, so each group
+// can be checked against its own rules. Scopes are ordered by their first path.
+func Resolve(root string, paths []string) ([]Scope, error) {
+	return resolve(dirTree(root), paths)
+}
+
+// ResolveFS groups paths like Resolve, reading the files from fsys
+func ResolveFS(fsys fs.FS, paths []string) ([]Scope, error) {
+	return resolve(fsTree{fsys}, paths)
+}
+
+func resolve(t tree, paths []string) ([]Scope, error) {
+	var scopes []Scope
+	index := make(map[string]int)
+
+	for _, p := range paths {
+		files, err := layeredFiles(t, p)
+		if err != nil {
+			return nil, err
+		}
+
+		id := strings.Join(files, "\x00")
+		if i, ok := index[id]; ok {
+			scopes[i].Paths = append(scopes[i].Paths, p)
+			continue
+		}
+
+		cfg, _, err := loadFor(t, p)
+		if err != nil {
+			return nil, err
+		}
+		index[id] = len(scopes)
+		scopes = append(scopes, Scope{Files: files, Paths: []string{p}, Config: cfg})
+	}
+	return scopes, nil
+}
+
+// Explain returns the effective settings for a repository path, sorted by
+// key, each with the file that set it ("default" for built-in defaults)
+func Explain(root, relPath string) ([]Setting, []string, error) {
+	t := dirTree(root)
+	files, err := layeredFiles(t, relPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v, err := mergeLayers(t, files)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources := make(map[string]string)
+	for _, file := range files {
+		layer, err := readLayer(t, file)
+		if err != nil {
+			return nil, nil, err
+		}
+		base, err := readBase(t, path.Dir(file), layer)
+		if err != nil {
+			return nil, nil, err
+		}
+		if base != nil {
+			baseLayer := viper.New()
+			baseLayer.SetConfigType("yaml")
+			if err := baseLayer.ReadConfig(bytes.NewReader(base)); err != nil {
+				return nil, nil, fmt.Errorf("error reading base config %s: %w", layerExtends(layer).Source, err)
+			}
+			for _, key := range baseLayer.AllKeys() {
+				sources[key] = fmt.Sprintf("%s (extends %s)", file, layerExtends(layer).Source)
+			}
+		}
+		for _, key := range layer.AllKeys() {
+			sources[key] = file
+		}
+	}
+
+	var settings []Setting
+	for _, key := range v.AllKeys() {
+		source, ok := sources[key]
+		if !ok {
+			source = "default"
+		}
+		settings = append(settings, Setting{Key: key, Value: v.Get(key), Source: source})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings, files, nil
+}
+
+// layeredFiles lists the configuration files applying to relPath, outermost
+// first, as slash-separated paths relative to root
+func layeredFiles(t tree, relPath string) ([]string, error) {
+	dir := path.Clean(filepath.ToSlash(relPath))
+	if !t.isDir(dir) {
+		dir = path.Dir(dir)
+	}
+
+	var files []string
+	for {
+		candidate := path.Join(dir, FileName)
+		if t.isFile(candidate) {
+			files = append([]string{candidate}, files...)
+
+			layer, err := readLayer(t, candidate)
+			if err != nil {
+				return nil, err
+			}
+			if layer.GetBool("root") {
+				break
+			}
+		}
+
+		if dir == "." || dir == "/" || dir == "" {
+			break
+		}
+		dir = path.Dir(dir)
+	}
+
+	if len(files) == 0 {
+		return nil, ErrNotFound
+	}
+	return files, nil
+}
+
+// mergeLayers merges the given files, outermost first, over the defaults
+func mergeLayers(t tree, files []string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	setDefaults(v)
+
+	for _, file := range files {
+		layer, err := readLayer(t, file)
+		if err != nil {
+			return nil, err
+		}
+		if err := mergeBase(v, t, path.Dir(file), layer); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		data, err := t.readFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading config file %s: %w", file, err)
+		}
+		if err := v.MergeConfig(bytes.NewReader(data)); err != nil {
+			return nil, fmt.Errorf("error reading config file %s: %w", file, err)
+		}
+	}
+	return v, nil
+}
+
+// readLayer reads a single configuration file without defaults
+func readLayer(t tree, file string) (*viper.Viper, error) {
+	data, err := t.readFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", file, err)
+	}
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(bytes.NewReader(data)); err != nil {
+		return nil, fmt.Errorf("error reading config file %s: %w", file, err)
+	}
+	return v, nil
+}
+
+// tree is where configuration files are read from, by slash-separated path
+// relative to its root
+type tree interface {
+	readFile(name string) ([]byte, error)
+	isFile(name string) bool
+	isDir(name string) bool
+}
+
+// dirTree is a directory on disk. Paths may leave it with "..", as a local
+// extends source next to the repository does.
+type dirTree string
+
+func (d dirTree) path(name string) string {
+	return filepath.Join(string(d), filepath.FromSlash(name))
+}
+
+func (d dirTree) readFile(name string) ([]byte, error) {
+	return os.ReadFile(d.path(name))
+}
+
+func (d dirTree) isFile(name string) bool {
+	info, err := os.Stat(d.path(name))
+	return err == nil && !info.IsDir()
+}
+
+func (d dirTree) isDir(name string) bool {
+	info, err := os.Stat(d.path(name))
+	return err == nil && info.IsDir()
+}
+
+// fsTree is an fs.FS, such as the tree of a git revision
+type fsTree struct {
+	fsys fs.FS
+}
+
+func (f fsTree) readFile(name string) ([]byte, error) {
+	name = path.Clean(name)
+	if !fs.ValidPath(name) {
+		return nil, fmt.Errorf("%s is outside the configuration tree: %w", name, fs.ErrNotExist)
+	}
+	return fs.ReadFile(f.fsys, name)
+}
+
+func (f fsTree) isFile(name string) bool {
+	info, err := fs.Stat(f.fsys, path.Clean(name))
+	return err == nil && !info.IsDir()
+}
+
+func (f fsTree) isDir(name string) bool {
+	info, err := fs.Stat(f.fsys, path.Clean(name))
+	return err == nil && info.IsDir()
+}
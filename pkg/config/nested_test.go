@@ -0,0 +1,165 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing/fstest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Nested configuration", func() {
+	var root string
+
+	write := func(rel, content string) {
+		full := filepath.Join(root, filepath.FromSlash(rel))
+		Expect(os.MkdirAll(filepath.Dir(full), 0o755)).To(Succeed())
+		Expect(os.WriteFile(full, []byte(content), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		root = GinkgoT().TempDir()
+		write(".jitt.yaml", "jira:\n  project: ABC\n  url: https://jira.example.com\n"+
+			"smart_commits:\n  transitions: [resolve]\n")
+		write("services/billing/.jitt.yaml", "jira:\n  project: BILL\n")
+		write("services/billing/api/handler.go", "package api\n")
+		write("web/index.html", "<html></html>\n")
+	})
+
+	Describe("LoadFor", func() {
+		It("should return the root config for paths without nested files", func() {
+			cfg, files, err := LoadFor(root, "web/index.html")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal([]string{".jitt.yaml"}))
+			Expect(cfg.Jira.Project).To(Equal("ABC"))
+		})
+
+		It("should let nested files override their parents key by key", func() {
+			cfg, files, err := LoadFor(root, "services/billing/api/handler.go")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal([]string{".jitt.yaml", "services/billing/.jitt.yaml"}))
+			Expect(cfg.Jira.Project).To(Equal("BILL"))
+			Expect(cfg.Jira.URL).To(Equal("https://jira.example.com"))
+			Expect(cfg.SmartCommits.Transitions).To(ConsistOf("resolve"))
+			Expect(cfg.Workflow.MainBranch).To(Equal("main"))
+		})
+
+		It("should replace lists rather than append to them", func() {
+			write("services/billing/.jitt.yaml", "smart_commits:\n  transitions: [close]\n")
+
+			cfg, _, err := LoadFor(root, "services/billing/api/handler.go")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.SmartCommits.Transitions).To(ConsistOf("close"))
+		})
+
+		It("should apply a directory's own file when given the directory", func() {
+			cfg, _, err := LoadFor(root, "services/billing")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Jira.Project).To(Equal("BILL"))
+		})
+
+		It("should stop inheriting at files marked root", func() {
+			write("services/billing/.jitt.yaml", "root: true\njira:\n  project: BILL\n")
+
+			cfg, files, err := LoadFor(root, "services/billing/api/handler.go")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal([]string{"services/billing/.jitt.yaml"}))
+			Expect(cfg.Jira.URL).To(BeEmpty())
+		})
+
+		It("should report malformed nested files", func() {
+			write("web/.jitt.yaml", "jira: [unclosed")
+
+			_, _, err := LoadFor(root, "web/index.html")
+			Expect(err).To(MatchError(ContainSubstring("error reading config file web/.jitt.yaml")))
+		})
+
+		It("should fail when no config file applies", func() {
+			Expect(os.Remove(filepath.Join(root, ".jitt.yaml"))).To(Succeed())
+
+			_, _, err := LoadFor(root, "web/index.html")
+			Expect(err).To(MatchError("config file not found"))
+		})
+	})
+
+	Describe("Resolve", func() {
+		It("should group paths by effective configuration", func() {
+			scopes, err := Resolve(root, []string{
+				"web/index.html", "services/billing/api/handler.go", "README.md", "services/billing/go.mod",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scopes).To(HaveLen(2))
+			Expect(scopes[0].Paths).To(Equal([]string{"web/index.html", "README.md"}))
+			Expect(scopes[0].Config.Jira.Project).To(Equal("ABC"))
+			Expect(scopes[1].Paths).To(Equal([]string{"services/billing/api/handler.go", "services/billing/go.mod"}))
+			Expect(scopes[1].Config.Jira.Project).To(Equal("BILL"))
+		})
+	})
+
+	Describe("LoadForFS and ResolveFS", func() {
+		It("should read the files from an fs.FS, ignoring the disk", func() {
+			fsys := fstest.MapFS{
+				".jitt.yaml":                  {Data: []byte("jira:\n  project: OPS\n")},
+				"services/billing/.jitt.yaml": {Data: []byte("jira:\n  project: PAY\n")},
+				"services/billing/main.go":    {Data: []byte("package main\n")},
+			}
+
+			cfg, files, err := LoadForFS(fsys, "services/billing/main.go")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(Equal([]string{".jitt.yaml", "services/billing/.jitt.yaml"}))
+			Expect(cfg.Jira.Project).To(Equal("PAY"))
+
+			scopes, err := ResolveFS(fsys, []string{"web/index.html", "services/billing/main.go"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(scopes).To(HaveLen(2))
+			Expect(scopes[0].Config.Jira.Project).To(Equal("OPS"))
+		})
+
+		It("should read vendored and relative bases from the same tree", func() {
+			fsys := fstest.MapFS{
+				".jitt.yaml":      {Data: []byte("extends:\n  source: https://example.com/base.git\n")},
+				".jitt/base.yaml": {Data: []byte("jira:\n  project: BASE\n")},
+				"web/.jitt.yaml":  {Data: []byte("extends:\n  source: ../shared.yaml\n")},
+				"shared.yaml":     {Data: []byte("jira:\n  url: https://jira.example.com\n")},
+			}
+
+			cfg, _, err := LoadForFS(fsys, "web/app.js")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Jira.Project).To(Equal("BASE"))
+			Expect(cfg.Jira.URL).To(Equal("https://jira.example.com"))
+		})
+
+		It("should refuse bases outside the tree", func() {
+			fsys := fstest.MapFS{".jitt.yaml": {Data: []byte("extends:\n  source: ../base.yaml\n")}}
+
+			_, _, err := LoadForFS(fsys, ".")
+			Expect(err).To(MatchError(ContainSubstring("outside the configuration tree")))
+		})
+
+		It("should refuse absolute bases, even when the file exists on disk", func() {
+			base := filepath.Join(GinkgoT().TempDir(), "base.yaml")
+			Expect(os.WriteFile(base, []byte("jira:\n  project: DISK\n"), 0o644)).To(Succeed())
+			fsys := fstest.MapFS{".jitt.yaml": {Data: []byte("extends:\n  source: " + base + "\n")}}
+
+			_, _, err := LoadForFS(fsys, ".")
+			Expect(err).To(MatchError(ContainSubstring("outside the configuration tree")))
+		})
+	})
+
+	Describe("Explain", func() {
+		It("should report each setting with the file that set it", func() {
+			settings, files, err := Explain(root, "services/billing/api")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(2))
+
+			sources := map[string]string{}
+			for _, s := range settings {
+				sources[s.Key] = s.Source
+			}
+			Expect(sources).To(HaveKeyWithValue("jira.project", "services/billing/.jitt.yaml"))
+			Expect(sources).To(HaveKeyWithValue("jira.url", ".jitt.yaml"))
+			Expect(sources).To(HaveKeyWithValue("workflow.main_branch", "default"))
+		})
+	})
+})
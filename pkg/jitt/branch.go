@@ -0,0 +1,32 @@
+package jitt
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// CheckBranch makes sure a branch name matches branch.pattern, unless it is
+// the main branch or matches one of branch.exempt
+func CheckBranch(cfg *Config, branch string) []Problem {
+	if cfg.Branch.Pattern == "" || branch == "" || branch == cfg.Workflow.MainBranch {
+		return nil
+	}
+	for _, glob := range cfg.Branch.Exempt {
+		if ok, _ := path.Match(glob, branch); ok {
+			return nil
+		}
+	}
+
+	problem := Problem{Rule: RuleBranchPattern, Severity: SeverityError}
+	pattern, err := regexp.Compile(cfg.Branch.Pattern)
+	if err != nil {
+		problem.Message = fmt.Sprintf("invalid branch.pattern: %v", err)
+		return []Problem{problem}
+	}
+	if pattern.MatchString(branch) {
+		return nil
+	}
+	problem.Message = fmt.Sprintf("branch %q does not match branch.pattern %s", branch, cfg.Branch.Pattern)
+	return []Problem{problem}
+}
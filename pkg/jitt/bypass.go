@@ -0,0 +1,53 @@
+package jitt
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// BypassTrailer is the trailer recording why a commit skipped jitt's checks
+const BypassTrailer = "Jitt-Skip"
+
+// bypassMarker matches a [skip-jitt: reason] marker anywhere in a message
+var bypassMarker = regexp.MustCompile(`(?i)\[skip-jitt:\s*([^\]\n]*?)\s*\]`)
+
+// BypassReason returns why a message skips jitt's checks: the value of its
+// Jitt-Skip trailer or of a [skip-jitt: reason] marker. A bypass without a
+// reason doesn't count.
+func BypassReason(message string) (string, bool) {
+	for _, t := range Trailers(message) {
+		if strings.EqualFold(t.Token, BypassTrailer) && t.Value != "" {
+			return t.Value, true
+		}
+	}
+	if m := bypassMarker.FindStringSubmatch(message); m != nil && m[1] != "" {
+		return m[1], true
+	}
+	return "", false
+}
+
+// BypassAllowed reports whether commits on branch may skip jitt's checks:
+// unless bypass.enabled is off or the branch matches bypass.disabled_branches
+func BypassAllowed(cfg *Config, branch string) bool {
+	if !cfg.Bypass.Enabled {
+		return false
+	}
+	for _, glob := range cfg.Bypass.DisabledBranches {
+		if ok, _ := path.Match(glob, branch); ok {
+			return false
+		}
+	}
+	return true
+}
+
+// RecordBypass adds a Jitt-Skip trailer with reason to message, unless the
+// message already has one
+func RecordBypass(message, reason string) string {
+	for _, t := range Trailers(message) {
+		if strings.EqualFold(t.Token, BypassTrailer) {
+			return message
+		}
+	}
+	return AppendTrailers(message, BypassTrailer, []string{reason})
+}
@@ -0,0 +1,46 @@
+package jitt
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Bypass", func() {
+	DescribeTable("BypassReason",
+		func(message, reason string, ok bool) {
+			got, found := BypassReason(message)
+			Expect(found).To(Equal(ok))
+			Expect(got).To(Equal(reason))
+		},
+		Entry("no bypass", "ABC-1 Fix login\n", "", false),
+		Entry("marker in the subject", "Hotfix prod outage [skip-jitt: Jira is down]\n", "Jira is down", true),
+		Entry("marker in any case", "Hotfix\n\n[Skip-Jitt:  incident 42 ]\n", "incident 42", true),
+		Entry("marker without a reason", "Hotfix [skip-jitt: ]\n", "", false),
+		Entry("trailer", "Hotfix\n\nJitt-Skip: Jira is down\n", "Jira is down", true),
+		Entry("empty trailer", "Hotfix\n\nJitt-Skip:\n", "", false),
+		Entry("trailer-like line outside the trailer block", "Hotfix\n\nJitt-Skip: no\n\nMore text\n", "", false),
+	)
+
+	Describe("BypassAllowed", func() {
+		It("should allow bypasses except where disabled", func() {
+			cfg := DefaultConfig()
+			Expect(BypassAllowed(cfg, "feature/ABC-1")).To(BeTrue())
+
+			cfg.Bypass.DisabledBranches = []string{"main", "release/*"}
+			Expect(BypassAllowed(cfg, "main")).To(BeFalse())
+			Expect(BypassAllowed(cfg, "release/1.2")).To(BeFalse())
+			Expect(BypassAllowed(cfg, "feature/ABC-1")).To(BeTrue())
+
+			cfg.Bypass.Enabled = false
+			Expect(BypassAllowed(cfg, "feature/ABC-1")).To(BeFalse())
+		})
+	})
+
+	Describe("RecordBypass", func() {
+		It("should add the reason as a trailer once", func() {
+			recorded := RecordBypass("Hotfix\n\nSigned-off-by: Jane <jane@example.com>\n", "Jira is down")
+			Expect(recorded).To(Equal("Hotfix\n\nSigned-off-by: Jane <jane@example.com>\nJitt-Skip: Jira is down"))
+			Expect(RecordBypass(recorded, "again")).To(Equal(recorded))
+		})
+	})
+})
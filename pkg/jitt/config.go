@@ -0,0 +1,80 @@
+package jitt
+
+import (
+	"io"
+	"io/fs"
+
+	"github.com/bbommarito/jitt/pkg/config"
+)
+
+// Config is a parsed .jitt.yaml. See the README for the meaning of each
+// setting. It and its sections are defined in the public package
+// github.com/bbommarito/jitt/pkg/config, which the jitt command uses too.
+type Config = config.Config
+
+// The sections of a Config
+type (
+	JiraConfig         = config.JiraConfig
+	CommitConfig       = config.CommitConfig
+	BranchConfig       = config.BranchConfig
+	WorkflowConfig     = config.WorkflowConfig
+	SmartCommitsConfig = config.SmartCommitsConfig
+	LintConfig         = config.LintConfig
+	LintRule           = config.LintRule
+	ConventionalRule   = config.ConventionalRule
+	LengthRule         = config.LengthRule
+	WordsRule          = config.WordsRule
+	ProjectMapping     = config.ProjectMapping
+	KeysConfig         = config.KeysConfig
+	ValidateConfig     = config.ValidateConfig
+	ExemptConfig       = config.ExemptConfig
+	BypassConfig       = config.BypassConfig
+	ExtendsConfig      = config.ExtendsConfig
+)
+
+// Ticket key positions (commit.position)
+const (
+	CommitPositionSubject = config.CommitPositionSubject
+	CommitPositionTrailer = config.CommitPositionTrailer
+)
+
+// Smart-commit modes (smart_commits.mode)
+const (
+	SmartCommitsOff      = config.SmartCommitsOff
+	SmartCommitsValidate = config.SmartCommitsValidate
+	SmartCommitsExecute  = config.SmartCommitsExecute
+)
+
+// Problem and lint rule severities
+const (
+	SeverityOff     = config.SeverityOff
+	SeverityWarning = config.SeverityWarning
+	SeverityError   = config.SeverityError
+)
+
+// DefaultConfig returns the configuration of a .jitt.yaml that sets nothing
+func DefaultConfig() *Config {
+	return config.Defaults()
+}
+
+// ParseConfig reads a .jitt.yaml document over the defaults. An extends
+// section is not followed; use LoadConfig to read a repository's files.
+func ParseConfig(r io.Reader) (*Config, error) {
+	return config.Parse(r)
+}
+
+// LoadConfig loads the configuration applying to relPath in the repository
+// checked out at root, merging nested .jitt.yaml files and base configs the
+// way the jitt command does
+func LoadConfig(root, relPath string) (*Config, error) {
+	cfg, _, err := config.LoadFor(root, relPath)
+	return cfg, err
+}
+
+// LoadConfigFS loads the configuration applying to relPath like LoadConfig,
+// reading the files from fsys, such as a commit's tree served by a forge.
+// Local base configs must lie inside fsys.
+func LoadConfigFS(fsys fs.FS, relPath string) (*Config, error) {
+	cfg, _, err := config.LoadForFS(fsys, relPath)
+	return cfg, err
+}
@@ -0,0 +1,47 @@
+package jitt
+
+import (
+	"strings"
+	"testing/fstest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseConfig", func() {
+	It("should apply the defaults under the document", func() {
+		cfg, err := ParseConfig(strings.NewReader("commit:\n  trailer: Jira\n"))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Commit.Trailer).To(Equal("Jira"))
+		Expect(cfg.Commit.Position).To(Equal(CommitPositionSubject))
+		Expect(cfg.Workflow.MainBranch).To(Equal("main"))
+		Expect(cfg.Worklog.SessionGap).To(Equal(2 * time.Hour))
+	})
+
+	It("should reject invalid YAML", func() {
+		_, err := ParseConfig(strings.NewReader("jira: [\n"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should match DefaultConfig for an empty document", func() {
+		cfg, err := ParseConfig(strings.NewReader(""))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg).To(Equal(DefaultConfig()))
+	})
+})
+
+var _ = Describe("LoadConfigFS", func() {
+	It("should merge the nested files applying to a path", func() {
+		fsys := fstest.MapFS{
+			".jitt.yaml":     {Data: []byte("jira:\n  project: ABC\n  url: https://jira.example.com\n")},
+			"web/.jitt.yaml": {Data: []byte("jira:\n  project: WEB\n")},
+		}
+
+		cfg, err := LoadConfigFS(fsys, "web/app.js")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Jira.Project).To(Equal("WEB"))
+		Expect(cfg.Jira.URL).To(Equal("https://jira.example.com"))
+	})
+})
@@ -0,0 +1,14 @@
+// Package jitt is the library behind the jitt command: it finds Jira ticket
+// keys in text, validates commit messages against a .jitt.yaml configuration
+// and checks branch names. It has no side effects - nothing is printed, no
+// process is exited and, unless a Validator is given a Transitions function,
+// nothing is fetched - so other tools can embed the same checks jitt runs.
+//
+//	cfg, err := jitt.LoadConfig(repoRoot, ".")
+//	if err != nil {
+//		return err
+//	}
+//	for _, problem := range jitt.ValidateMessage(cfg, message, changedFiles) {
+//		fmt.Println(problem)
+//	}
+package jitt
@@ -0,0 +1,73 @@
+package jitt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CommitInfo describes the commit a message belongs to, for the exemptions
+// in validate.exempt
+type CommitInfo struct {
+	AuthorEmail    string
+	CommitterEmail string
+	// Files are the paths the commit touches (repository-relative, slash-separated)
+	Files []string
+	// Merge is set for commits with more than one parent
+	Merge bool
+}
+
+// revertedCommit matches the line git revert adds to the message
+var revertedCommit = regexp.MustCompile(`(?m)^This reverts commit [0-9a-f]{7,}`)
+
+// ExemptReason returns why validate.exempt lets a commit with message skip
+// validation, or false when it doesn't
+func ExemptReason(cfg *Config, c CommitInfo, message string) (string, bool) {
+	exempt := cfg.Validate.Exempt
+	switch {
+	case exempt.Merges && c.Merge:
+		return "merge commit", true
+	case exempt.Reverts && IsRevert(message):
+		return "revert commit", true
+	}
+	if glob, ok := matchEmail(exempt.Authors, c.AuthorEmail); ok {
+		return fmt.Sprintf("author %s matches %s", c.AuthorEmail, glob), true
+	}
+	if glob, ok := matchEmail(exempt.Committers, c.CommitterEmail); ok {
+		return fmt.Sprintf("committer %s matches %s", c.CommitterEmail, glob), true
+	}
+	if len(exempt.Paths) > 0 && len(c.Files) > 0 && allFilesMatch(exempt.Paths, c.Files) {
+		return "only touches exempt paths", true
+	}
+	return "", false
+}
+
+// IsRevert reports whether message is one git revert wrote: a subject
+// starting with Revert " or a "This reverts commit <sha>" line
+func IsRevert(message string) bool {
+	return strings.HasPrefix(message, `Revert "`) || revertedCommit.MatchString(message)
+}
+
+// matchEmail returns the first glob matching email, ignoring case. Only *
+// is special in the globs, so addresses like renovate[bot]@... match literally.
+func matchEmail(globs []string, email string) (string, bool) {
+	if email == "" {
+		return "", false
+	}
+	for _, glob := range globs {
+		pattern := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(glob), `\*`, ".*") + "$"
+		if ok, _ := regexp.MatchString(pattern, email); ok {
+			return glob, true
+		}
+	}
+	return "", false
+}
+
+func allFilesMatch(patterns, files []string) bool {
+	for _, file := range files {
+		if !anyFileMatches(patterns, []string{file}) {
+			return false
+		}
+	}
+	return true
+}
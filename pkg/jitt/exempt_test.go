@@ -0,0 +1,58 @@
+package jitt
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExemptReason", func() {
+	var cfg *Config
+
+	BeforeEach(func() {
+		cfg = DefaultConfig()
+		cfg.Validate.Exempt = ExemptConfig{
+			Authors:    []string{"*[bot]@users.noreply.github.com"},
+			Committers: []string{"release@example.com"},
+			Paths:      []string{"docs/**", "*.md"},
+			Merges:     true,
+			Reverts:    true,
+		}
+	})
+
+	DescribeTable("exemptions",
+		func(c CommitInfo, message, reason string) {
+			got, ok := ExemptReason(cfg, c, message)
+			Expect(ok).To(Equal(reason != ""))
+			Expect(got).To(Equal(reason))
+		},
+		Entry("an ordinary commit", CommitInfo{AuthorEmail: "dev@example.com", Files: []string{"main.go"}},
+			"Fix login\n", ""),
+		Entry("a bot author, matched literally and ignoring case",
+			CommitInfo{AuthorEmail: "29139614+Renovate[bot]@users.noreply.github.com"}, "Update deps\n",
+			"author 29139614+Renovate[bot]@users.noreply.github.com matches *[bot]@users.noreply.github.com"),
+		Entry("brackets are not character classes", CommitInfo{AuthorEmail: "renovateb@users.noreply.github.com"},
+			"Update deps\n", ""),
+		Entry("an exempt committer", CommitInfo{CommitterEmail: "release@example.com"}, "Release 1.2\n",
+			"committer release@example.com matches release@example.com"),
+		Entry("only exempt paths", CommitInfo{Files: []string{"docs/guide/setup.txt", "README.md"}}, "Fix typos\n",
+			"only touches exempt paths"),
+		Entry("exempt and other paths", CommitInfo{Files: []string{"docs/setup.txt", "main.go"}}, "Fix typos\n", ""),
+		Entry("no files", CommitInfo{}, "Empty\n", ""),
+		Entry("a merge", CommitInfo{Merge: true}, "Merge branch 'topic'\n", "merge commit"),
+		Entry("a revert", CommitInfo{}, "Revert \"ABC-1 Fix login\"\n\nThis reverts commit 0123456789abcdef.\n",
+			"revert commit"),
+	)
+
+	It("should exempt nothing by default", func() {
+		cfg = DefaultConfig()
+		c := CommitInfo{AuthorEmail: "bot@example.com", Files: []string{"README.md"}, Merge: true}
+		_, ok := ExemptReason(cfg, c, "Revert \"x\"\n\nThis reverts commit 0123456.\n")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should recognise messages git revert wrote", func() {
+		Expect(IsRevert("Revert \"ABC-1 Fix login\"\n")).To(BeTrue())
+		Expect(IsRevert("ABC-2 Undo login fix\n\nThis reverts commit 0123456789abcdef.\n")).To(BeTrue())
+		Expect(IsRevert("Reverting to the old login form\n")).To(BeFalse())
+	})
+})
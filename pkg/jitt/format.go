@@ -0,0 +1,198 @@
+package jitt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultCommitFormat is the layout FixMessage uses when commit.format is not set
+const DefaultCommitFormat = "{key} {message}"
+
+// keyListPattern matches one or more ticket keys separated by spaces or commas
+const keyListPattern = `[A-Z][A-Z0-9_]+-[1-9][0-9]*(?:[ ,]+[A-Z][A-Z0-9_]+-[1-9][0-9]*)*`
+
+// placedKeyPattern matches a key along with the brackets and colon often written around it
+var placedKeyPattern = regexp.MustCompile(`[\[(]?\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b[\])]?:?`)
+
+// CommitFormatPattern compiles a commit.format into a pattern matching the
+// subjects that follow it
+func CommitFormatPattern(format string) (*regexp.Regexp, error) {
+	if !strings.Contains(format, "{key}") {
+		return nil, fmt.Errorf("commit.format %q must contain {key}", format)
+	}
+	pattern := regexp.QuoteMeta(format)
+	pattern = strings.ReplaceAll(pattern, `\{key\}`, keyListPattern)
+	pattern = strings.ReplaceAll(pattern, `\{message\}`, `\S.*`)
+	return regexp.Compile("^" + pattern + "$")
+}
+
+// renderCommitFormat lays a subject out according to format
+func renderCommitFormat(format string, keys []string, message string) string {
+	subject := strings.ReplaceAll(format, "{key}", strings.Join(keys, " "))
+	return strings.ReplaceAll(subject, "{message}", message)
+}
+
+// checkCommitFormat makes sure the subject follows commit.format, when set
+// and keys belong in the subject
+func checkCommitFormat(cfg *Config, message string) string {
+	if cfg.Commit.Format == "" || TrailerMode(cfg) {
+		return ""
+	}
+
+	pattern, err := CommitFormatPattern(cfg.Commit.Format)
+	if err != nil {
+		return err.Error()
+	}
+
+	subject, _, _ := strings.Cut(message, "\n")
+	if pattern.MatchString(subject) {
+		return ""
+	}
+
+	example := "ABC-123"
+	if cfg.Jira.Project != "" {
+		example = cfg.Jira.Project + "-123"
+	}
+	return fmt.Sprintf("subject does not follow commit.format %q (e.g. %s); 'jitt validate --fix' can rewrite it",
+		cfg.Commit.Format, renderCommitFormat(cfg.Commit.Format, []string{example}, "Fix the login form"))
+}
+
+// FixMessage rewrites message so its ticket keys are where commit.position
+// wants them: upper-cased, deduplicated and laid out by commit.format in the
+// subject, or moved to commit.trailer trailers. Keys are taken from the
+// subject, then the body, then the branch name. It returns the fixed message
+// and a description of each change made.
+func FixMessage(cfg *Config, message, branch string) (string, []string, error) {
+	format := cfg.Commit.Format
+	if format == "" {
+		format = DefaultCommitFormat
+	}
+	if _, err := CommitFormatPattern(format); err != nil && !TrailerMode(cfg) {
+		return "", nil, err
+	}
+
+	var changes []string
+	message = normalizeKeyCase(cfg, message, &changes)
+
+	subject, body, hasBody := strings.Cut(message, "\n")
+	bodyKeys := TicketKeys(cfg, body)
+	branchKeys := BranchKeys(cfg, branch)
+
+	keys := TicketKeys(cfg, subject)
+	switch {
+	case len(keys) > 0:
+	case len(bodyKeys) > 0:
+		keys = bodyKeys
+		if !TrailerMode(cfg) {
+			changes = append(changes, fmt.Sprintf("moved %s from the body into the subject", strings.Join(keys, ", ")))
+		}
+	case len(branchKeys) > 0:
+		keys = branchKeys
+		changes = append(changes, fmt.Sprintf("added %s from branch %s", strings.Join(keys, ", "), branch))
+	default:
+		return "", nil, fmt.Errorf("no ticket key found in the message or the branch name")
+	}
+
+	if mentions := KeysInProjects(cfg.KnownProjects(), keyPattern.FindAllString(subject, -1)); len(mentions) > len(keys) {
+		changes = append(changes, "removed repeated keys from the subject")
+	}
+
+	text := StripKeys(subject, keys)
+	if text == "" {
+		return "", nil, fmt.Errorf("the subject has no text besides the ticket key")
+	}
+
+	fixed := text
+	if !TrailerMode(cfg) {
+		fixed = renderCommitFormat(format, keys, text)
+	}
+	if fixed != subject {
+		changes = append(changes, fmt.Sprintf("subject: %q → %q", subject, fixed))
+	}
+	if hasBody {
+		fixed += "\n" + body
+	}
+
+	if TrailerMode(cfg) {
+		var missing []string
+		for _, key := range keys {
+			if !containsString(ReferencedKeys(cfg, fixed), key) {
+				missing = append(missing, key)
+			}
+		}
+		if len(missing) > 0 {
+			fixed = AppendTrailers(fixed, cfg.Commit.Trailer, missing)
+			changes = append(changes, fmt.Sprintf("added trailer %s: %s", cfg.Commit.Trailer, strings.Join(missing, ", ")))
+		}
+	}
+	return fixed, changes, nil
+}
+
+// AddKey puts key where commit.position wants it in a message referencing
+// no ticket: laid out by commit.format in the subject, or in a
+// commit.trailer trailer. Unlike FixMessage it adds key as given, whatever
+// project it belongs to and whatever other keys the body mentions.
+func AddKey(cfg *Config, message, key string) (string, error) {
+	if TrailerMode(cfg) {
+		return AppendTrailers(message, cfg.Commit.Trailer, []string{key}), nil
+	}
+
+	format := cfg.Commit.Format
+	if format == "" {
+		format = DefaultCommitFormat
+	}
+	if _, err := CommitFormatPattern(format); err != nil {
+		return "", err
+	}
+
+	subject, body, hasBody := strings.Cut(message, "\n")
+	text := strings.TrimSpace(subject)
+	if text == "" {
+		return "", fmt.Errorf("the subject is empty")
+	}
+	fixed := renderCommitFormat(format, []string{key}, text)
+	if hasBody {
+		fixed += "\n" + body
+	}
+	return fixed, nil
+}
+
+// StripKeys removes keys, with the brackets and colons around them, from a
+// subject and tidies the separators left behind
+func StripKeys(subject string, keys []string) string {
+	text := placedKeyPattern.ReplaceAllStringFunc(subject, func(match string) string {
+		if containsString(keys, keyPattern.FindString(match)) {
+			return ""
+		}
+		return match
+	})
+	return strings.Trim(strings.Join(strings.Fields(text), " "), " -:|,")
+}
+
+// normalizeKeyCase upper-cases ticket keys of known projects written in
+// another case (abc-123 → ABC-123). Without known projects nothing is changed,
+// since words like utf-8 would look like keys.
+func normalizeKeyCase(cfg *Config, message string, changes *[]string) string {
+	p := NewKeyParser(cfg)
+	p.IgnoreCase = true
+
+	var b strings.Builder
+	last := 0
+	seen := make(map[string]bool)
+	for _, m := range p.Parse(message) {
+		written := message[m.Start:m.End]
+		if written == m.Key {
+			continue
+		}
+		if !seen[written] {
+			seen[written] = true
+			*changes = append(*changes, fmt.Sprintf("%s → %s", written, m.Key))
+		}
+		b.WriteString(message[last:m.Start])
+		b.WriteString(m.Key)
+		last = m.End
+	}
+	b.WriteString(message[last:])
+	return b.String()
+}
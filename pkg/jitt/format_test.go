@@ -0,0 +1,42 @@
+package jitt
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("FixMessage",
+	func(format, message, branch, expected string) {
+		cfg := &Config{
+			Jira:   JiraConfig{Project: "ABC"},
+			Commit: CommitConfig{Format: format},
+		}
+		fixed, _, err := FixMessage(cfg, message, branch)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fixed).To(Equal(expected))
+	},
+	Entry("moves a trailing key to the front", "", "fix bug ABC-123", "main", "ABC-123 fix bug"),
+	Entry("follows commit.format", "[{key}] {message}", "fix bug (ABC-123)", "main", "[ABC-123] fix bug"),
+	Entry("normalizes key casing", "{key}: {message}", "abc-123 fix bug", "main", "ABC-123: fix bug"),
+	Entry("deduplicates repeated keys", "", "ABC-1 fix ABC-1 bug ABC-2", "main", "ABC-1 ABC-2 fix bug"),
+	Entry("drops separators left behind", "", "fix bug - ABC-7", "main", "ABC-7 fix bug"),
+	Entry("keeps other projects' keys in place", "", "mention XYZ-1 in ABC-2", "main", "ABC-2 mention XYZ-1 in"),
+	Entry("takes the key from the body", "", "fix bug\n\nSee ABC-9", "main", "ABC-9 fix bug\n\nSee ABC-9"),
+	Entry("takes the key from the branch, whatever its case", "", "fix bug", "feature/abc-5-login", "ABC-5 fix bug"),
+	Entry("leaves canonical messages alone", "", "ABC-1 fix bug\n\nbody", "main", "ABC-1 fix bug\n\nbody"),
+)
+
+var _ = DescribeTable("AddKey",
+	func(commit CommitConfig, message, expected string) {
+		cfg := &Config{Jira: JiraConfig{Project: "ABC"}, Commit: commit}
+		fixed, err := AddKey(cfg, message, "XYZ-123")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fixed).To(Equal(expected))
+	},
+	Entry("puts the key in front by default", CommitConfig{}, "fix bug", "XYZ-123 fix bug"),
+	Entry("follows commit.format, keeping the body", CommitConfig{Format: "[{key}] {message}"},
+		"fix bug\n\nRelated to ABC-9", "[XYZ-123] fix bug\n\nRelated to ABC-9"),
+	Entry("adds a trailer in trailer mode, whatever the body mentions",
+		CommitConfig{Position: CommitPositionTrailer, Trailer: "Refs"},
+		"fix bug\n\nRelated to ABC-9", "fix bug\n\nRelated to ABC-9\n\nRefs: XYZ-123"),
+)
@@ -0,0 +1,274 @@
+package jitt
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// keyPattern matches Jira issue keys such as ABC-123
+var keyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[1-9][0-9]*\b`)
+
+// keyCandidatePattern matches anything shaped like a ticket key in any case;
+// KeyParser decides which of them count
+var keyCandidatePattern = regexp.MustCompile(`(?i)\b([a-z][a-z0-9_]+)-([0-9]+)\b`)
+
+// urlPattern matches URLs, whose paths often contain key-like segments
+var urlPattern = regexp.MustCompile(`\b[A-Za-z][A-Za-z0-9+.-]*://\S+`)
+
+// inlineCodePattern matches a `code span` on a single line
+var inlineCodePattern = regexp.MustCompile("`[^`\n]+`")
+
+// notProjects are prefixes of well-known identifiers shaped like ticket keys:
+// encodings, hashes, standards, advisories, licenses and architectures
+var notProjects = []string{
+	"UTF", "UCS", "SHA", "MD", "CRC", "AES", "RSA", "HMAC", "ISO", "IEC", "IEEE", "RFC", "ECMA",
+	"CVE", "CWE", "PEP", "TLS", "SSL", "HTTP", "GPL", "LGPL", "AGPL", "MPL", "BSD", "X86",
+}
+
+// KeyMatch is one ticket key found in text. Start and End are byte offsets
+// of the key as written; Line and Column are 1-based, counting characters.
+type KeyMatch struct {
+	Key    string
+	Start  int
+	End    int
+	Line   int
+	Column int
+}
+
+// KeyParser finds ticket keys in text. The zero value accepts any upper-case
+// key outside URLs and code, except well-known identifiers such as UTF-8.
+type KeyParser struct {
+	// Projects, when set, are the project keys accepted, even when the
+	// built-in or Deny lists name them
+	Projects []string
+	// AnyProject also accepts keys of projects not in Projects, unless denied
+	AnyProject bool
+	// Deny lists more prefixes never taken for project keys
+	Deny []string
+	// Min and Max bound issue numbers; zero leaves that side open
+	Min int
+	Max int
+	// IgnoreCase accepts keys of Projects written in any case (abc-123),
+	// reporting them upper-cased. Other projects must be upper-case, or
+	// words like utf-8 or re-2 would look like keys.
+	IgnoreCase bool
+}
+
+// NewKeyParser returns the parser for the keys cfg recognises: those of its
+// known projects, or any project when it knows none, within keys.min and keys.max
+func NewKeyParser(cfg *Config) KeyParser {
+	return KeyParser{
+		Projects: cfg.KnownProjects(),
+		Deny:     cfg.Keys.Deny,
+		Min:      cfg.Keys.Min,
+		Max:      cfg.Keys.Max,
+	}
+}
+
+// Parse returns every key in text, in order of appearance
+func (p KeyParser) Parse(text string) []KeyMatch {
+	skipped := skippedSpans(text)
+	var matches []KeyMatch
+	// Line of the last match and where it starts, counted forward as we go
+	line, lineStart, counted := 1, 0, 0
+	for _, m := range keyCandidatePattern.FindAllStringSubmatchIndex(text, -1) {
+		if inSpans(skipped, m[0]) || versionFollows(text, m[1]) {
+			continue
+		}
+		project, number := text[m[2]:m[3]], text[m[4]:m[5]]
+		if !p.acceptProject(project) || !p.acceptNumber(number) {
+			continue
+		}
+
+		line += strings.Count(text[counted:m[0]], "\n")
+		if i := strings.LastIndex(text[counted:m[0]], "\n"); i >= 0 {
+			lineStart = counted + i + 1
+		}
+		counted = m[0]
+		matches = append(matches, KeyMatch{
+			Key:    strings.ToUpper(project) + "-" + number,
+			Start:  m[0],
+			End:    m[1],
+			Line:   line,
+			Column: utf8.RuneCountInString(text[lineStart:m[0]]) + 1,
+		})
+	}
+	return matches
+}
+
+// Keys returns the unique keys in text, in order of appearance
+func (p KeyParser) Keys(text string) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, m := range p.Parse(text) {
+		if !seen[m.Key] {
+			seen[m.Key] = true
+			keys = append(keys, m.Key)
+		}
+	}
+	return keys
+}
+
+func (p KeyParser) acceptProject(project string) bool {
+	upper := strings.ToUpper(project)
+	known := containsString(p.Projects, upper)
+	switch {
+	case project != upper && !(p.IgnoreCase && known):
+		return false
+	case known:
+		return true
+	case len(p.Projects) > 0 && !p.AnyProject:
+		return false
+	}
+	return !containsString(notProjects, upper) && !containsString(p.Deny, upper)
+}
+
+func (p KeyParser) acceptNumber(number string) bool {
+	if number[0] == '0' {
+		return false
+	}
+	if p.Min == 0 && p.Max == 0 {
+		return true
+	}
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		// Too long to be an issue number anyone meant to bound
+		return p.Max == 0
+	}
+	return n >= p.Min && (p.Max == 0 || n <= p.Max)
+}
+
+// versionFollows reports whether a candidate ending at end continues like a
+// version number (JDK-11.0.2), so it is not a key
+func versionFollows(text string, end int) bool {
+	return end+1 < len(text) && text[end] == '.' && text[end+1] >= '0' && text[end+1] <= '9'
+}
+
+// skippedSpans returns the byte ranges of text where keys are not looked
+// for: URLs, inline code and fenced code blocks, sorted by start
+func skippedSpans(text string) [][2]int {
+	var spans [][2]int
+	for _, m := range urlPattern.FindAllStringIndex(text, -1) {
+		spans = append(spans, [2]int{m[0], m[1]})
+	}
+	for _, m := range inlineCodePattern.FindAllStringIndex(text, -1) {
+		spans = append(spans, [2]int{m[0], m[1]})
+	}
+
+	fenceStart := -1
+	offset := 0
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			if fenceStart < 0 {
+				fenceStart = offset
+			} else {
+				spans = append(spans, [2]int{fenceStart, offset + len(line)})
+				fenceStart = -1
+			}
+		}
+		offset += len(line)
+	}
+	if fenceStart >= 0 {
+		spans = append(spans, [2]int{fenceStart, len(text)})
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	return spans
+}
+
+func inSpans(spans [][2]int, offset int) bool {
+	for _, s := range spans {
+		if s[0] > offset {
+			return false
+		}
+		if offset < s[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// FindKeys returns the unique ticket keys mentioned in text, in order of
+// appearance, with the zero KeyParser's rules
+func FindKeys(text string) []string {
+	return KeyParser{}.Keys(text)
+}
+
+// IsKey reports whether s is exactly one ticket key
+func IsKey(s string) bool {
+	return s != "" && keyPattern.FindString(s) == s
+}
+
+// TicketKeys returns the unique keys in text that cfg recognises; with no
+// projects configured every key counts
+func TicketKeys(cfg *Config, text string) []string {
+	return NewKeyParser(cfg).Keys(text)
+}
+
+// BranchKeys returns the keys in a branch name that cfg recognises. Unlike
+// in messages, keys of known projects count in any case (feature/abc-123).
+func BranchKeys(cfg *Config, branch string) []string {
+	p := NewKeyParser(cfg)
+	p.IgnoreCase = true
+	return p.Keys(branch)
+}
+
+// KeysInProjects filters keys down to those belonging to one of projects,
+// or returns them all when projects is empty
+func KeysInProjects(projects, keys []string) []string {
+	if len(projects) == 0 {
+		return keys
+	}
+
+	var filtered []string
+	for _, key := range keys {
+		if containsString(projects, KeyProject(key)) {
+			filtered = append(filtered, key)
+		}
+	}
+	return filtered
+}
+
+// KeyProject returns the project part of a ticket key: ABC for ABC-123
+func KeyProject(key string) string {
+	project, _, _ := strings.Cut(key, "-")
+	return project
+}
+
+// ReferencedKeys returns the ticket keys that count as the message's ticket
+// reference: anywhere in the message, or in trailer mode only those in
+// commit.trailer trailers
+func ReferencedKeys(cfg *Config, message string) []string {
+	return KeysInProjects(cfg.KnownProjects(), CandidateKeys(cfg, message))
+}
+
+// CandidateKeys returns every key mentioned where commit.position expects
+// ticket references, whether or not the config knows its project
+func CandidateKeys(cfg *Config, message string) []string {
+	p := NewKeyParser(cfg)
+	p.AnyProject = true
+	if !TrailerMode(cfg) {
+		return p.Keys(message)
+	}
+
+	var values []string
+	for _, t := range Trailers(message) {
+		if strings.EqualFold(t.Token, cfg.Commit.Trailer) {
+			values = append(values, t.Value)
+		}
+	}
+	return p.Keys(strings.Join(values, "\n"))
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,56 @@
+package jitt
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func FuzzKeyParser(f *testing.F) {
+	for _, seed := range []string{
+		"ABC-123 Fix login",
+		"feature/abc-12-login",
+		"UTF-8 SHA-256 ISO-8601 https://x.example/ABC-1 `ABC-2`",
+		"```\nABC-3\n```\nABC-4",
+		"Voilà ABC-5\r\nABC-00 ABC-99999999999999999999 JDK-11.0.2",
+		"Refs: ABC-6, ABC-7\n~~~",
+	} {
+		f.Add(seed, 0, 0, true)
+	}
+
+	f.Fuzz(func(t *testing.T, text string, min, max int, ignoreCase bool) {
+		p := KeyParser{Projects: []string{"ABC", "UTF"}, AnyProject: true, Min: min, Max: max, IgnoreCase: ignoreCase}
+
+		matches := p.Parse(text)
+		seen := make(map[string]bool)
+		for i, m := range matches {
+			if i > 0 && m.Start < matches[i-1].End {
+				t.Fatalf("overlapping matches %+v and %+v", matches[i-1], m)
+			}
+			if m.Start < 0 || m.End > len(text) || !strings.EqualFold(text[m.Start:m.End], m.Key) {
+				t.Fatalf("match %+v does not cover its key in %q", m, text)
+			}
+			if !IsKey(m.Key) {
+				t.Fatalf("reported %q, which is not a key", m.Key)
+			}
+			if m.Line != strings.Count(text[:m.Start], "\n")+1 {
+				t.Fatalf("match %+v is on the wrong line", m)
+			}
+			lineStart := strings.LastIndex(text[:m.Start], "\n") + 1
+			if m.Column != utf8.RuneCountInString(text[lineStart:m.Start])+1 {
+				t.Fatalf("match %+v is in the wrong column", m)
+			}
+			seen[m.Key] = true
+		}
+
+		keys := p.Keys(text)
+		if len(keys) != len(seen) {
+			t.Fatalf("Keys returned %v for matches %+v", keys, matches)
+		}
+		for _, key := range keys {
+			if !seen[key] {
+				t.Fatalf("Keys returned %q, which Parse did not find", key)
+			}
+		}
+	})
+}
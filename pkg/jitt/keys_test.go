@@ -0,0 +1,69 @@
+package jitt
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("FindKeys",
+	func(text string, expected []string) {
+		Expect(FindKeys(text)).To(Equal(expected))
+	},
+	Entry("unique keys in order", "ABC-2 and XYZ-1, again ABC-2", []string{"ABC-2", "XYZ-1"}),
+	Entry("keys in branch names", "feature/ABC-12-login", []string{"ABC-12"}),
+	Entry("keys in brackets and trailers", "[ABC-1] Fix\n\nRefs: XYZ-3", []string{"ABC-1", "XYZ-3"}),
+	Entry("no lower-case or zero-padded keys", "abc-3 ABC-0 ABC-012", []string(nil)),
+	Entry("no encodings, hashes or standards", "UTF-8 SHA-256 ISO-8601 CVE-2024-1234 X86-64", []string(nil)),
+	Entry("no version numbers", "upgrade JDK-11.0.2", []string(nil)),
+	Entry("a sentence may end after a key", "Fixes ABC-1.", []string{"ABC-1"}),
+	Entry("nothing inside URLs", "see https://ci.example.com/job/ABC-9/ for ABC-10", []string{"ABC-10"}),
+	Entry("nothing in inline code", "rename `LEGACY-1` for ABC-4", []string{"ABC-4"}),
+	Entry("nothing in fenced code", "ABC-5\n```\nERR-42 at line 3\n```\nDEF-6", []string{"ABC-5", "DEF-6"}),
+	Entry("nothing in unclosed fences", "ABC-5\n~~~\nERR-42", []string{"ABC-5"}),
+)
+
+var _ = Describe("KeyParser", func() {
+	It("should report positions as lines and character columns", func() {
+		Expect(KeyParser{}.Parse("Fix ABC-1\n\nVoilà: ABC-22")).To(Equal([]KeyMatch{
+			{Key: "ABC-1", Start: 4, End: 9, Line: 1, Column: 5},
+			{Key: "ABC-22", Start: 19, End: 25, Line: 3, Column: 8},
+		}))
+	})
+
+	It("should only accept allowlisted projects, even denied ones", func() {
+		p := KeyParser{Projects: []string{"ABC", "ISO"}}
+		Expect(p.Keys("ABC-1 XYZ-2 ISO-8601")).To(Equal([]string{"ABC-1", "ISO-8601"}))
+
+		p.AnyProject = true
+		Expect(p.Keys("ABC-1 XYZ-2 UTF-8")).To(Equal([]string{"ABC-1", "XYZ-2"}))
+	})
+
+	It("should accept allowlisted projects in any case when asked", func() {
+		p := KeyParser{Projects: []string{"ABC"}, IgnoreCase: true}
+		Expect(p.Parse("feature/abc-7-login utf-8")).To(ConsistOf(
+			KeyMatch{Key: "ABC-7", Start: 8, End: 13, Line: 1, Column: 9}))
+		Expect(KeyParser{IgnoreCase: true}.Keys("abc-7")).To(BeEmpty())
+	})
+
+	It("should apply numeric ranges and extra denied prefixes", func() {
+		p := KeyParser{Min: 10, Max: 999, Deny: []string{"JDK"}}
+		Expect(p.Keys("ABC-9 ABC-10 ABC-999 ABC-1000 JDK-17 ABC-99999999999999999999")).To(
+			Equal([]string{"ABC-10", "ABC-999"}))
+	})
+
+	It("should take its rules from the config", func() {
+		cfg := DefaultConfig()
+		cfg.Jira.Project = "ABC"
+		cfg.Keys = KeysConfig{Allow: []string{"OPS"}, Max: 100}
+
+		Expect(TicketKeys(cfg, "ABC-1 OPS-2 XYZ-3 ABC-101")).To(Equal([]string{"ABC-1", "OPS-2"}))
+		Expect(BranchKeys(cfg, "bugfix/ops-5")).To(Equal([]string{"OPS-5"}))
+		Expect(CandidateKeys(cfg, "ABC-1 XYZ-3 SHA-256")).To(Equal([]string{"ABC-1", "XYZ-3"}))
+	})
+
+	It("should recognise a single key", func() {
+		Expect(IsKey("ABC-12")).To(BeTrue())
+		Expect(IsKey("ABC-12 ")).To(BeFalse())
+		Expect(IsKey("")).To(BeFalse())
+	})
+})
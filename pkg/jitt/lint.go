@@ -0,0 +1,202 @@
+package jitt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// leadingKeysPattern matches ticket keys written before the rest of a subject:
+// "ABC-1 ", "ABC-1: " or "[ABC-1] "
+var leadingKeysPattern = regexp.MustCompile(`^(?:\[?[A-Z][A-Z0-9_]+-[1-9][0-9]*\]?:?\s+)+`)
+
+// conventionalPattern matches a Conventional Commits header: type(scope)!: description
+var conventionalPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: (\S.*)$`)
+
+// nonImperativeWords are common ways of starting a subject in the past tense
+// or third person that the suffix checks in isImperative don't catch
+var nonImperativeWords = map[string]bool{
+	"adds": true, "fixes": true, "updates": true, "removes": true, "changes": true,
+	"implements": true, "creates": true, "improves": true, "renames": true, "moves": true,
+	"deletes": true, "bumps": true, "merges": true, "allows": true, "makes": true,
+	"uses": true, "refactors": true, "cleans": true, "handles": true, "introduces": true,
+	"made": true, "wrote": true, "built": true, "ran": true, "did": true, "began": true,
+}
+
+// edIngWords end in -ed or -ing but are fine in a subject
+var edIngWords = map[string]bool{
+	"need": true, "embed": true, "feed": true, "seed": true, "shred": true, "speed": true,
+	"proceed": true, "exceed": true, "succeed": true, "bleed": true, "breed": true,
+	"bring": true, "string": true, "ring": true, "sing": true, "ping": true, "spring": true,
+}
+
+// lintMessage checks message against the enabled lint rules
+func lintMessage(cfg *Config, message string) []Problem {
+	rules := cfg.Lint
+	lines := strings.Split(message, "\n")
+	subject := lines[0]
+
+	var problems []Problem
+	report := func(rule, severity string, line, column int, format string, args ...any) {
+		problems = append(problems, Problem{
+			Rule:     rule,
+			Severity: normalizeSeverity(severity),
+			Line:     line,
+			Column:   column,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	// Offsets (in bytes) of the subject's description, after any leading
+	// ticket keys and Conventional Commits header
+	descStart := len(leadingKeysPattern.FindString(subject))
+
+	if m := conventionalPattern.FindStringSubmatchIndex(subject[descStart:]); m != nil {
+		commitType := subject[descStart+m[2] : descStart+m[3]]
+		if enabled(rules.Conventional.Severity) && len(rules.Conventional.Types) > 0 &&
+			!containsString(rules.Conventional.Types, commitType) {
+			report(RuleConventional, rules.Conventional.Severity, 1, column(subject, descStart+m[2]),
+				"unknown commit type %q (allowed: %s)", commitType, strings.Join(rules.Conventional.Types, ", "))
+		}
+		if m[4] >= 0 && enabled(rules.Conventional.Severity) && len(rules.Conventional.Scopes) > 0 {
+			scope := subject[descStart+m[4] : descStart+m[5]]
+			if !containsString(rules.Conventional.Scopes, scope) {
+				report(RuleConventional, rules.Conventional.Severity, 1, column(subject, descStart+m[4]),
+					"unknown scope %q (allowed: %s)", scope, strings.Join(rules.Conventional.Scopes, ", "))
+			}
+		}
+		descStart += m[8]
+	} else if enabled(rules.Conventional.Severity) {
+		report(RuleConventional, rules.Conventional.Severity, 1, column(subject, descStart),
+			"subject does not follow Conventional Commits (type(scope): description)")
+	}
+	descStart += len(leadingKeysPattern.FindString(subject[descStart:]))
+
+	if enabled(rules.SubjectLength.Severity) && rules.SubjectLength.Max > 0 {
+		if n := utf8.RuneCountInString(subject); n > rules.SubjectLength.Max {
+			report(RuleSubjectLength, rules.SubjectLength.Severity, 1, rules.SubjectLength.Max+1,
+				"subject is %d characters long (max %d)", n, rules.SubjectLength.Max)
+		}
+	}
+
+	if enabled(rules.ImperativeMood.Severity) {
+		word := firstWord(subject[descStart:])
+		if word != "" && !isImperative(word) {
+			report(RuleImperativeMood, rules.ImperativeMood.Severity, 1, column(subject, descStart),
+				"start the subject with an imperative verb (\"Add\", not %q)", word)
+		}
+	}
+
+	if enabled(rules.SubjectPeriod.Severity) && strings.HasSuffix(subject, ".") && !strings.HasSuffix(subject, "...") {
+		report(RuleSubjectPeriod, rules.SubjectPeriod.Severity, 1, utf8.RuneCountInString(subject),
+			"subject ends with a period")
+	}
+
+	if enabled(rules.BlankLine.Severity) && len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		report(RuleBlankLine, rules.BlankLine.Severity, 2, 1, "separate the subject from the body with a blank line")
+	}
+
+	if enabled(rules.BodyWrap.Severity) && rules.BodyWrap.Max > 0 {
+		for i := 1; i < len(lines); i++ {
+			if !wrappable(lines[i]) {
+				continue
+			}
+			if n := utf8.RuneCountInString(lines[i]); n > rules.BodyWrap.Max {
+				report(RuleBodyWrap, rules.BodyWrap.Severity, i+1, rules.BodyWrap.Max+1,
+					"line is %d characters long (wrap at %d)", n, rules.BodyWrap.Max)
+			}
+		}
+	}
+
+	if enabled(rules.ForbiddenWords.Severity) {
+		for _, word := range rules.ForbiddenWords.Words {
+			pattern := regexp.MustCompile(`(?i)(?:^|\W)(` + regexp.QuoteMeta(word) + `)(?:\W|$)`)
+			for i, line := range lines {
+				for _, m := range pattern.FindAllStringSubmatchIndex(line, -1) {
+					report(RuleForbiddenWords, rules.ForbiddenWords.Severity, i+1, column(line, m[2]),
+						"forbidden word %q", line[m[2]:m[3]])
+				}
+			}
+		}
+	}
+
+	if enabled(rules.RequiredTrailers.Severity) {
+		var present []string
+		for _, t := range Trailers(message) {
+			present = append(present, t.Token)
+		}
+		for _, trailer := range rules.RequiredTrailers.Words {
+			if !containsEqualFold(present, trailer) {
+				report(RuleRequiredTrailers, rules.RequiredTrailers.Severity, len(lines), 1,
+					"missing required trailer %q", trailer)
+			}
+		}
+	}
+
+	return problems
+}
+
+func enabled(severity string) bool {
+	severity = strings.ToLower(strings.TrimSpace(severity))
+	return severity != "" && severity != SeverityOff
+}
+
+// normalizeSeverity maps a configured severity to warning or error; anything
+// other than "warning" is treated as an error so typos fail loudly
+func normalizeSeverity(severity string) string {
+	if strings.EqualFold(strings.TrimSpace(severity), SeverityWarning) {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+// column converts a byte offset in line into a 1-based character column
+func column(line string, offset int) int {
+	return utf8.RuneCountInString(line[:offset]) + 1
+}
+
+func firstWord(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimRight(fields[0], ",:;.!")
+}
+
+// isImperative guesses whether word is a verb in the imperative mood. It is a
+// heuristic: it only flags common past-tense, -ing and third-person forms.
+func isImperative(word string) bool {
+	w := strings.ToLower(word)
+	if nonImperativeWords[w] {
+		return false
+	}
+	if edIngWords[w] {
+		return true
+	}
+	if len(w) > 4 && strings.HasSuffix(w, "ed") {
+		return false
+	}
+	if len(w) > 5 && strings.HasSuffix(w, "ing") {
+		return false
+	}
+	return true
+}
+
+// wrappable reports whether a body line should respect the wrap width;
+// indented code and unbreakable lines such as long URLs are exempt
+func wrappable(line string) bool {
+	if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+		return false
+	}
+	return strings.ContainsAny(strings.TrimSpace(line), " \t")
+}
+
+func containsEqualFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, target) {
+			return true
+		}
+	}
+	return false
+}
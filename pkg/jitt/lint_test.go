@@ -0,0 +1,122 @@
+package jitt
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("isImperative",
+	func(word string, expected bool) {
+		Expect(isImperative(word)).To(Equal(expected))
+	},
+	Entry("imperative verb", "Add", true),
+	Entry("past tense", "Added", false),
+	Entry("gerund", "Fixing", false),
+	Entry("third person", "fixes", false),
+	Entry("irregular past tense", "made", false),
+	Entry("verb ending in -eed", "Speed", true),
+	Entry("verb ending in -ing", "Bring", true),
+	Entry("short -ed word", "Shed", true),
+)
+
+var _ = Describe("lintMessage", func() {
+	var cfg *Config
+
+	BeforeEach(func() {
+		cfg = &Config{}
+	})
+
+	It("should report nothing when no rule is enabled", func() {
+		Expect(lintMessage(cfg, "Added stuff.\nno blank line")).To(BeEmpty())
+	})
+
+	Describe("conventional", func() {
+		BeforeEach(func() {
+			cfg.Lint.Conventional = ConventionalRule{
+				Severity: "error", Types: []string{"feat", "fix"}, Scopes: []string{"api"},
+			}
+		})
+
+		It("should accept conventional subjects, after leading ticket keys too", func() {
+			Expect(lintMessage(cfg, "feat(api)!: add pagination")).To(BeEmpty())
+			Expect(lintMessage(cfg, "ABC-1 fix: handle empty pages")).To(BeEmpty())
+			Expect(lintMessage(cfg, "[ABC-1] fix: handle empty pages")).To(BeEmpty())
+		})
+
+		It("should report subjects without a type", func() {
+			Expect(lintMessage(cfg, "ABC-1 handle empty pages")).To(ConsistOf(Problem{
+				Rule: "conventional", Severity: "error", Line: 1, Column: 7,
+				Message: "subject does not follow Conventional Commits (type(scope): description)",
+			}))
+		})
+
+		It("should report unknown types and scopes at their position", func() {
+			problems := lintMessage(cfg, "chore(web): tidy up")
+			Expect(problems).To(HaveLen(2))
+			Expect(problems[0].String()).To(Equal(`1:1: unknown commit type "chore" (allowed: feat, fix) [conventional]`))
+			Expect(problems[1].String()).To(Equal(`1:7: unknown scope "web" (allowed: api) [conventional]`))
+		})
+	})
+
+	It("should check subject length", func() {
+		cfg.Lint.SubjectLength = LengthRule{Severity: "warning", Max: 10}
+
+		Expect(lintMessage(cfg, "Add things")).To(BeEmpty())
+		Expect(lintMessage(cfg, "Add more things")).To(ConsistOf(Problem{
+			Rule: "subject-length", Severity: "warning", Line: 1, Column: 11,
+			Message: "subject is 15 characters long (max 10)",
+		}))
+	})
+
+	It("should check the mood of the first word after keys and type", func() {
+		cfg.Lint.ImperativeMood = LintRule{Severity: "error"}
+
+		Expect(lintMessage(cfg, "feat: ABC-1 add pagination")).To(BeEmpty())
+		problems := lintMessage(cfg, "feat: ABC-1 added pagination")
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].String()).To(Equal(`1:13: start the subject with an imperative verb ("Add", not "added") [imperative-mood]`))
+	})
+
+	It("should reject a trailing period but not an ellipsis", func() {
+		cfg.Lint.SubjectPeriod = LintRule{Severity: "error"}
+
+		Expect(lintMessage(cfg, "Add pagination...")).To(BeEmpty())
+		Expect(lintMessage(cfg, "Add pagination.")).To(ConsistOf(HaveField("Column", 15)))
+	})
+
+	It("should require a blank line after the subject", func() {
+		cfg.Lint.BlankLine = LintRule{Severity: "error"}
+
+		Expect(lintMessage(cfg, "Add pagination\n\nBody")).To(BeEmpty())
+		Expect(lintMessage(cfg, "Add pagination\nBody")).To(ConsistOf(HaveField("Line", 2)))
+	})
+
+	It("should check the body wrap width, skipping code and URLs", func() {
+		cfg.Lint.BodyWrap = LengthRule{Severity: "error", Max: 20}
+
+		problems := lintMessage(cfg, "Add pagination\n\n"+
+			"This line is definitely too long\n"+
+			"    indented code can be as long as it likes\n"+
+			"https://example.com/a/very/long/url/that/cannot/wrap\n")
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0].String()).To(Equal("3:21: line is 32 characters long (wrap at 20) [body-wrap]"))
+	})
+
+	It("should find forbidden words as whole words", func() {
+		cfg.Lint.ForbiddenWords = WordsRule{Severity: "error", Words: []string{"wip", "fix later"}}
+
+		Expect(lintMessage(cfg, "Add wipe button")).To(BeEmpty())
+		problems := lintMessage(cfg, "WIP: add pagination\n\nWill fix later.")
+		Expect(problems).To(HaveLen(2))
+		Expect(problems[0].String()).To(Equal(`1:1: forbidden word "WIP" [forbidden-words]`))
+		Expect(problems[1].String()).To(Equal(`3:6: forbidden word "fix later" [forbidden-words]`))
+	})
+
+	It("should require trailers in the last paragraph", func() {
+		cfg.Lint.RequiredTrailers = WordsRule{Severity: "error", Words: []string{"Signed-off-by"}}
+
+		Expect(lintMessage(cfg, "Add pagination\n\nsigned-off-by: Jane <jane@example.com>")).To(BeEmpty())
+		Expect(lintMessage(cfg, "Signed-off-by: Jane <jane@example.com>")).To(ConsistOf(
+			HaveField("Message", `missing required trailer "Signed-off-by"`)))
+	})
+})
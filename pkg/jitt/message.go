@@ -0,0 +1,21 @@
+package jitt
+
+import "strings"
+
+// ScissorsLine marks the start of the diff appended by `git commit --verbose`
+const ScissorsLine = "# ------------------------ >8 ------------------------"
+
+// StripComments removes git's comment lines and anything below the scissors
+// line from a commit message file, leaving the message itself
+func StripComments(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if line == ScissorsLine {
+			break
+		}
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
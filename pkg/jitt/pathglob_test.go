@@ -0,0 +1,22 @@
+package jitt
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable("MatchPath",
+	func(pattern, name string, expected bool) {
+		Expect(MatchPath(pattern, name)).To(Equal(expected))
+	},
+	Entry("** spans directories", "services/billing/**", "services/billing/api/handler.go", true),
+	Entry("** matches direct children", "services/billing/**", "services/billing/go.mod", true),
+	Entry("** does not match siblings", "services/billing/**", "services/billingx/go.mod", false),
+	Entry("** in the middle", "services/**/*.sql", "services/billing/db/001.sql", true),
+	Entry("** in the middle matches zero directories", "services/**/*.sql", "services/001.sql", true),
+	Entry("single * stays in one directory", "web/*", "web/src/app.ts", false),
+	Entry("bare patterns match the file name anywhere", "*.md", "docs/guide/intro.md", true),
+	Entry("bare patterns don't match directories", "*.md", "docs.md/file.txt", false),
+	Entry("leading slash is ignored", "/web/**", "web/index.html", true),
+	Entry("exact paths", "Makefile", "Makefile", true),
+)
@@ -0,0 +1,52 @@
+package jitt
+
+import "fmt"
+
+// The rules a Problem can come from
+const (
+	RuleTicketReference  = "ticket-reference"
+	RuleCommitFormat     = "commit-format"
+	RuleSmartCommits     = "smart-commits"
+	RuleBranchPattern    = "branch-pattern"
+	RuleConventional     = "conventional"
+	RuleSubjectLength    = "subject-length"
+	RuleImperativeMood   = "imperative-mood"
+	RuleSubjectPeriod    = "subject-period"
+	RuleBlankLine        = "blank-line"
+	RuleBodyWrap         = "body-wrap"
+	RuleForbiddenWords   = "forbidden-words"
+	RuleRequiredTrailers = "required-trailers"
+	RuleBypass           = "bypass"
+)
+
+// Problem is one finding about a commit message or branch. Line and Column
+// are 1-based positions in the message, or zero when not tied to a position.
+type Problem struct {
+	Rule     string
+	Severity string
+	Line     int
+	Column   int
+	Message  string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return fmt.Sprintf("%s [%s]", p.Message, p.Rule)
+	}
+	return fmt.Sprintf("%d:%d: %s [%s]", p.Line, p.Column, p.Message, p.Rule)
+}
+
+// IsError reports whether the problem fails validation rather than being a warning
+func (p Problem) IsError() bool {
+	return p.Severity != SeverityWarning
+}
+
+// HasErrors reports whether any of problems fails validation
+func HasErrors(problems []Problem) bool {
+	for _, p := range problems {
+		if p.IsError() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package jitt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// smartCommandPattern matches a smart-commit command such as #comment or #start-progress
+var smartCommandPattern = regexp.MustCompile(`(?:^|\s)#([A-Za-z][A-Za-z0-9_-]*)`)
+
+// workDurationPattern matches Jira duration notation: 1w 2d 4h 30m
+var workDurationPattern = regexp.MustCompile(`^(?:\d+[wdhm]\s*)+$`)
+
+// SmartCommand is one #command found on a commit message line, applying to
+// every ticket key mentioned on that same line
+type SmartCommand struct {
+	Keys []string
+	Name string
+	Args string
+	Line int
+}
+
+// ParseSmartCommands extracts smart-commit commands from a message. As with
+// Jira's own integration, commands only count on lines that mention a ticket.
+func ParseSmartCommands(cfg *Config, message string) []SmartCommand {
+	var commands []SmartCommand
+	for i, line := range strings.Split(message, "\n") {
+		keys := TicketKeys(cfg, line)
+		if len(keys) == 0 {
+			continue
+		}
+
+		matches := smartCommandPattern.FindAllStringSubmatchIndex(line, -1)
+		for j, m := range matches {
+			end := len(line)
+			if j+1 < len(matches) {
+				end = matches[j+1][0]
+			}
+			commands = append(commands, SmartCommand{
+				Keys: keys,
+				Name: strings.ToLower(line[m[2]:m[3]]),
+				Args: strings.TrimSpace(line[m[3]:end]),
+				Line: i + 1,
+			})
+		}
+	}
+	return commands
+}
+
+// IsTransition reports whether the command names a workflow transition
+func (c SmartCommand) IsTransition() bool {
+	return c.Name != "comment" && c.Name != "time"
+}
+
+// TransitionName turns #start-progress into "start progress"
+func (c SmartCommand) TransitionName() string {
+	return strings.ReplaceAll(c.Name, "-", " ")
+}
+
+// Check validates the command's arguments without talking to Jira
+func (c SmartCommand) Check() error {
+	switch c.Name {
+	case "comment":
+		if c.Args == "" {
+			return fmt.Errorf("line %d: #comment needs some text", c.Line)
+		}
+	case "time":
+		if _, _, err := SplitWorkTime(c.Args); err != nil {
+			return fmt.Errorf("line %d: %w", c.Line, err)
+		}
+	}
+	return nil
+}
+
+// SplitWorkTime splits "#time" arguments into the duration and an optional worklog comment
+func SplitWorkTime(args string) (duration, comment string, err error) {
+	fields := strings.Fields(args)
+	n := 0
+	for n < len(fields) && workDurationPattern.MatchString(fields[n]) {
+		n++
+	}
+	if n == 0 {
+		return "", "", fmt.Errorf("invalid #time value %q (expected e.g. 1w 2d 4h 30m)", args)
+	}
+	return strings.Join(fields[:n], " "), strings.Join(fields[n:], " "), nil
+}
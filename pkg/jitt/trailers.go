@@ -0,0 +1,70 @@
+package jitt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// trailerPattern matches a git trailer line such as "Signed-off-by: Jane <jane@example.com>"
+var trailerPattern = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*):\s*(.*)$`)
+
+// Trailer is one "Token: value" line of a message's trailer block
+type Trailer struct {
+	Token string
+	Value string
+}
+
+// Trailers returns the trailers in the last paragraph of a message, which
+// git only treats as a trailer block when it follows the subject and every
+// line in it is a trailer
+func Trailers(message string) []Trailer {
+	lines := trailerBlock(strings.Split(strings.TrimRight(message, "\n"), "\n"))
+
+	trailers := make([]Trailer, 0, len(lines))
+	for _, line := range lines {
+		m := trailerPattern.FindStringSubmatch(line)
+		if m == nil {
+			return nil
+		}
+		trailers = append(trailers, Trailer{Token: m[1], Value: strings.TrimSpace(m[2])})
+	}
+	return trailers
+}
+
+// trailerBlock returns the last paragraph of lines, or nothing when the
+// message is a single paragraph
+func trailerBlock(lines []string) []string {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := end
+	for start > 0 && strings.TrimSpace(lines[start-1]) != "" {
+		start--
+	}
+	if start == 0 {
+		return nil
+	}
+	return lines[start:end]
+}
+
+// TrailerMode reports whether ticket keys belong in trailers rather than the subject
+func TrailerMode(cfg *Config) bool {
+	return cfg.Commit.Position == CommitPositionTrailer
+}
+
+// AppendTrailers adds one "token: key" trailer per key to message, joining
+// its trailer block when it has one
+func AppendTrailers(message, token string, keys []string) string {
+	message = strings.TrimRight(message, "\n")
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %s", token, key))
+	}
+
+	if len(Trailers(message)) > 0 {
+		return message + "\n" + strings.Join(lines, "\n")
+	}
+	return message + "\n\n" + strings.Join(lines, "\n")
+}
@@ -0,0 +1,65 @@
+package jitt
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Trailers", func() {
+	trailerConfig := func() *Config {
+		return &Config{
+			Jira:   JiraConfig{Project: "ABC"},
+			Commit: CommitConfig{Position: CommitPositionTrailer, Trailer: "Refs"},
+		}
+	}
+
+	Describe("Trailers", func() {
+		It("should read the trailer block after the subject", func() {
+			Expect(Trailers("Fix login\n\nBody text\n\nRefs: ABC-1\nSigned-off-by: Jane <jane@example.com>\n")).To(Equal([]Trailer{
+				{Token: "Refs", Value: "ABC-1"},
+				{Token: "Signed-off-by", Value: "Jane <jane@example.com>"},
+			}))
+		})
+
+		It("should not treat the subject or prose as trailers", func() {
+			Expect(Trailers("Refs: ABC-1")).To(BeEmpty())
+			Expect(Trailers("Fix login\n\nNote: this is prose\nthat wraps")).To(BeEmpty())
+		})
+	})
+
+	Describe("ReferencedKeys", func() {
+		It("should only count the configured trailer in trailer mode", func() {
+			cfg := trailerConfig()
+			Expect(ReferencedKeys(cfg, "ABC-1 Fix login")).To(BeEmpty())
+			Expect(ReferencedKeys(cfg, "Fix login\n\nrefs: ABC-1, ABC-2")).To(Equal([]string{"ABC-1", "ABC-2"}))
+		})
+
+		It("should count keys anywhere in subject mode", func() {
+			Expect(ReferencedKeys(&Config{}, "Fix login\n\nRefs: ABC-1")).To(Equal([]string{"ABC-1"}))
+		})
+	})
+
+	Describe("AppendTrailers", func() {
+		It("should start a trailer block or join the existing one", func() {
+			Expect(AppendTrailers("Fix login\n", "Refs", []string{"ABC-1"})).To(Equal("Fix login\n\nRefs: ABC-1"))
+			Expect(AppendTrailers("Fix login\n\nSigned-off-by: Jane", "Refs", []string{"ABC-1", "ABC-2"})).To(
+				Equal("Fix login\n\nSigned-off-by: Jane\nRefs: ABC-1\nRefs: ABC-2"))
+		})
+	})
+
+	Describe("FixMessage in trailer mode", func() {
+		It("should move keys from the subject into trailers", func() {
+			fixed, changes, err := FixMessage(trailerConfig(), "abc-1 Fix login", "main")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fixed).To(Equal("Fix login\n\nRefs: ABC-1"))
+			Expect(changes).To(ContainElement("added trailer Refs: ABC-1"))
+		})
+
+		It("should leave messages with the trailer alone", func() {
+			fixed, changes, err := FixMessage(trailerConfig(), "Fix login\n\nRefs: ABC-1", "main")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fixed).To(Equal("Fix login\n\nRefs: ABC-1"))
+			Expect(changes).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,182 @@
+package jitt
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Transition is a workflow transition available on an issue: its name and
+// the status it leads to. Smart-commit commands may name either.
+type Transition struct {
+	Name   string
+	Status string
+}
+
+// Validator checks commit messages against a Config
+type Validator struct {
+	Config *Config
+	// Transitions, when set, returns the transitions available on an issue
+	// so smart-commit transition commands can be checked against them.
+	// Without it they are checked against smart_commits.transitions. Issues
+	// whose transitions can't be fetched are not checked.
+	Transitions func(ctx context.Context, key string) ([]Transition, error)
+}
+
+// ValidateMessage returns the problems found in the message of a commit
+// touching files (repository-relative, slash-separated), without asking Jira
+// about smart-commit transitions
+func ValidateMessage(cfg *Config, message string, files []string) []Problem {
+	return Validator{Config: cfg}.ValidateMessage(context.Background(), message, files)
+}
+
+// ValidateMessage returns the problems found in the message of a commit
+// touching files (repository-relative, slash-separated)
+func (v Validator) ValidateMessage(ctx context.Context, message string, files []string) []Problem {
+	cfg := v.Config
+	var problems []Problem
+	if problem := checkTicketReference(cfg, message, files); problem != "" {
+		problems = append(problems, Problem{Rule: RuleTicketReference, Severity: SeverityError, Message: problem})
+	}
+	if problem := checkCommitFormat(cfg, message); problem != "" {
+		problems = append(problems, Problem{Rule: RuleCommitFormat, Severity: SeverityError,
+			Line: 1, Column: 1, Message: problem})
+	}
+	for _, problem := range v.checkSmartCommands(ctx, message) {
+		problems = append(problems, Problem{Rule: RuleSmartCommits, Severity: SeverityError, Message: problem})
+	}
+	return append(problems, lintMessage(cfg, message)...)
+}
+
+// checkTicketReference makes sure the message references a ticket, and when
+// the touched files are mapped to projects, a ticket of one of those projects
+func checkTicketReference(cfg *Config, message string, files []string) string {
+	keys := ReferencedKeys(cfg, message)
+	owners := OwningProjects(cfg, files)
+
+	if len(owners) == 0 {
+		if len(keys) > 0 {
+			return ""
+		}
+		example := "ABC-123"
+		if cfg.Jira.Project != "" {
+			example = cfg.Jira.Project + "-123"
+		}
+		if TrailerMode(cfg) {
+			return fmt.Sprintf("commit message has no %s: trailer referencing a Jira ticket (e.g. %s: %s)",
+				cfg.Commit.Trailer, cfg.Commit.Trailer, example)
+		}
+		return fmt.Sprintf("commit message does not reference a Jira ticket (e.g. %s)", example)
+	}
+
+	if len(KeysInProjects(owners, keys)) > 0 {
+		return ""
+	}
+	problem := fmt.Sprintf("commit touches files owned by %s but references none of their tickets (e.g. %s-123)",
+		strings.Join(owners, ", "), owners[0])
+	if len(keys) > 0 {
+		problem += fmt.Sprintf("; found %s", strings.Join(keys, ", "))
+	}
+	return problem
+}
+
+// checkSmartCommands checks the smart-commit commands in message. Transition
+// commands are checked against v.Transitions when set, falling back to
+// smart_commits.transitions; with neither, any transition name is accepted.
+func (v Validator) checkSmartCommands(ctx context.Context, message string) []string {
+	cfg := v.Config
+	if cfg.SmartCommits.Mode == SmartCommitsOff {
+		return nil
+	}
+
+	var problems []string
+	available := make(map[string][]Transition)
+
+	for _, c := range ParseSmartCommands(cfg, message) {
+		if err := c.Check(); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if !c.IsTransition() {
+			continue
+		}
+
+		if v.Transitions == nil {
+			if len(cfg.SmartCommits.Transitions) > 0 && !containsFold(cfg.SmartCommits.Transitions, c.TransitionName()) {
+				problems = append(problems, fmt.Sprintf("line %d: unknown transition #%s (allowed: %s)",
+					c.Line, c.Name, strings.Join(cfg.SmartCommits.Transitions, ", ")))
+			}
+			continue
+		}
+
+		for _, key := range c.Keys {
+			transitions, ok := available[key]
+			if !ok {
+				transitions, _ = v.Transitions(ctx, key)
+				available[key] = transitions
+			}
+			if transitions != nil && !hasTransition(transitions, c.TransitionName()) {
+				problems = append(problems, fmt.Sprintf("line %d: unknown transition #%s for %s (available: %s)",
+					c.Line, c.Name, key, transitionNames(transitions)))
+			}
+		}
+	}
+	return problems
+}
+
+func hasTransition(transitions []Transition, target string) bool {
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, target) || strings.EqualFold(t.Status, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func transitionNames(transitions []Transition) string {
+	if len(transitions) == 0 {
+		return "none"
+	}
+
+	names := make([]string, 0, len(transitions))
+	for _, t := range transitions {
+		names = append(names, t.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func containsFold(values []string, target string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.ReplaceAll(v, "-", " "), target) {
+			return true
+		}
+	}
+	return false
+}
+
+// OwningProjects returns the projects the projects mappings assign to any of files
+func OwningProjects(cfg *Config, files []string) []string {
+	var owners []string
+	for _, m := range cfg.Projects {
+		if !anyFileMatches(m.Paths, files) {
+			continue
+		}
+		for _, key := range m.Keys {
+			if !containsString(owners, key) {
+				owners = append(owners, key)
+			}
+		}
+	}
+	return owners
+}
+
+func anyFileMatches(patterns, files []string) bool {
+	for _, file := range files {
+		for _, pattern := range patterns {
+			if MatchPath(pattern, file) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package jitt
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ValidateMessage", func() {
+	var cfg *Config
+
+	BeforeEach(func() {
+		var err error
+		cfg, err = ParseConfig(strings.NewReader("jira:\n  project: ABC\n" +
+			"projects:\n  - paths: [\"web/**\"]\n    keys: [WEB]\n"))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should accept a message referencing a ticket", func() {
+		Expect(ValidateMessage(cfg, "ABC-1 Add login form", nil)).To(BeEmpty())
+	})
+
+	It("should require a ticket reference", func() {
+		problems := ValidateMessage(cfg, "Add login form", nil)
+		Expect(problems).To(ConsistOf(HaveField("Rule", RuleTicketReference)))
+		Expect(HasErrors(problems)).To(BeTrue())
+		Expect(problems[0].String()).To(Equal(
+			"commit message does not reference a Jira ticket (e.g. ABC-123) [ticket-reference]"))
+	})
+
+	It("should require a ticket of the project owning the files", func() {
+		Expect(ValidateMessage(cfg, "WEB-2 Add login form", []string{"web/app.js"})).To(BeEmpty())
+		Expect(ValidateMessage(cfg, "ABC-1 Add login form", []string{"web/app.js"})).To(ConsistOf(
+			HaveField("Message", ContainSubstring("owned by WEB"))))
+	})
+
+	It("should not count warnings as errors", func() {
+		cfg.Lint.SubjectPeriod = LintRule{Severity: SeverityWarning}
+
+		problems := ValidateMessage(cfg, "ABC-1 Add login form.", nil)
+		Expect(problems).To(ConsistOf(HaveField("Rule", RuleSubjectPeriod)))
+		Expect(HasErrors(problems)).To(BeFalse())
+	})
+
+	Describe("smart-commit transitions", func() {
+		transitions := func(_ context.Context, key string) ([]Transition, error) {
+			if key == "ABC-9" {
+				return nil, errors.New("not found")
+			}
+			return []Transition{{Name: "Start Progress", Status: "In Progress"}}, nil
+		}
+
+		It("should check transitions against the given function", func() {
+			v := Validator{Config: cfg, Transitions: transitions}
+
+			Expect(v.ValidateMessage(context.Background(), "ABC-1 Add form #start-progress", nil)).To(BeEmpty())
+			Expect(v.ValidateMessage(context.Background(), "ABC-1 Add form #in-progress", nil)).To(BeEmpty())
+			Expect(v.ValidateMessage(context.Background(), "ABC-1 Add form #close", nil)).To(ConsistOf(
+				HaveField("Message", "line 1: unknown transition #close for ABC-1 (available: Start Progress)")))
+		})
+
+		It("should skip issues whose transitions can't be fetched", func() {
+			v := Validator{Config: cfg, Transitions: transitions}
+			Expect(v.ValidateMessage(context.Background(), "ABC-9 Add form #close", nil)).To(BeEmpty())
+		})
+
+		It("should fall back to smart_commits.transitions", func() {
+			cfg.SmartCommits.Transitions = []string{"start-progress"}
+
+			Expect(ValidateMessage(cfg, "ABC-1 Add form #start-progress", nil)).To(BeEmpty())
+			Expect(ValidateMessage(cfg, "ABC-1 Add form #close #time soon", nil)).To(ConsistOf(
+				HaveField("Message", "line 1: unknown transition #close (allowed: start-progress)"),
+				HaveField("Message", `line 1: invalid #time value "soon" (expected e.g. 1w 2d 4h 30m)`),
+			))
+		})
+	})
+})
+
+var _ = Describe("CheckBranch", func() {
+	cfg := &Config{
+		Branch:   BranchConfig{Pattern: `^feature/[A-Z]+-[0-9]+`, Exempt: []string{"release/*"}},
+		Workflow: WorkflowConfig{MainBranch: "main"},
+	}
+
+	It("should accept matching, main and exempt branches", func() {
+		Expect(CheckBranch(cfg, "feature/ABC-1-login")).To(BeEmpty())
+		Expect(CheckBranch(cfg, "main")).To(BeEmpty())
+		Expect(CheckBranch(cfg, "release/1.2")).To(BeEmpty())
+	})
+
+	It("should report other branches", func() {
+		Expect(CheckBranch(cfg, "login")).To(ConsistOf(Problem{
+			Rule: RuleBranchPattern, Severity: SeverityError,
+			Message: `branch "login" does not match branch.pattern ^feature/[A-Z]+-[0-9]+`,
+		}))
+	})
+})